@@ -0,0 +1,102 @@
+// Command benchcompare compares two runs of the benchmarks and flags regressions.
+//
+// Save the output of two benchmark runs, then compare them:
+//
+//	go test -run xxx -bench . -benchmem -count 5 ./... > old.txt
+//	go test -run xxx -bench . -benchmem -count 5 ./... > new.txt
+//	go run ./benchmarks/benchcompare old.txt new.txt
+//
+// Exits with a status of 1 if any benchmark regressed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/peake100/turnup-go/benchmarks"
+	"os"
+	"text/tabwriter"
+)
+
+func readResults(path string) ([]*benchmarks.Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return benchmarks.ParseResults(file)
+}
+
+// Formats a change as a signed percentage.
+func formatChange(change float64) string {
+	return fmt.Sprintf("%+.1f%%", change*100)
+}
+
+func printComparisons(comparisons []*benchmarks.Comparison) (regressions int) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "benchmark\ttime\tbytes\tallocs\t")
+
+	for _, comparison := range comparisons {
+		name := comparison.Package + "." + comparison.Name
+		if comparison.Old == nil {
+			_, _ = fmt.Fprintf(writer, "%v\tnew\tnew\tnew\t\n", name)
+			continue
+		}
+
+		note := ""
+		if comparison.Regression {
+			note = "REGRESSION"
+			regressions++
+		}
+		_, _ = fmt.Fprintf(
+			writer,
+			"%v\t%v\t%v\t%v\t%v\n",
+			name,
+			formatChange(comparison.TimeChange),
+			formatChange(comparison.BytesChange),
+			formatChange(comparison.AllocsChange),
+			note,
+		)
+	}
+
+	_ = writer.Flush()
+	return regressions
+}
+
+func main() {
+	thresholds := benchmarks.DefaultThresholds
+	flag.Float64Var(
+		&thresholds.Time, "time", thresholds.Time, "allowed slowdown as a fraction",
+	)
+	flag.Float64Var(
+		&thresholds.Bytes, "bytes", thresholds.Bytes, "allowed growth in bytes per op",
+	)
+	flag.Float64Var(
+		&thresholds.Allocs,
+		"allocs",
+		thresholds.Allocs,
+		"allowed growth in allocations per op",
+	)
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		_, _ = fmt.Fprintln(os.Stderr, "usage: benchcompare [flags] old.txt new.txt")
+		os.Exit(2)
+	}
+
+	oldResults, err := readResults(flag.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	newResults, err := readResults(flag.Arg(1))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	comparisons := benchmarks.Compare(oldResults, newResults, thresholds)
+	if regressions := printComparisons(comparisons); regressions > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "%v benchmarks regressed\n", regressions)
+		os.Exit(1)
+	}
+}
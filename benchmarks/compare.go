@@ -0,0 +1,213 @@
+// Package benchmarks has tools for comparing benchmark runs, to catch performance
+// regressions in the prediction engine. The benchmarks themselves live with the
+// models package.
+package benchmarks
+
+import (
+	"bufio"
+	"github.com/peake100/turnup-go/errs"
+	"golang.org/x/xerrors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Result is the result of a benchmark, averaged over every time it was run.
+type Result struct {
+	Package string
+	// The name of the benchmark, without the GOMAXPROCS suffix.
+	Name string
+	// The number of runs averaged, from running the benchmarks with -count.
+	Runs int
+
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+}
+
+// Matches the GOMAXPROCS suffix go test adds to benchmark names.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// ParseResults reads the output of ``go test -bench``. Benchmarks run more than once
+// are averaged. Bytes and allocations are only reported when run with -benchmem.
+func ParseResults(reader io.Reader) ([]*Result, error) {
+	type resultKey struct {
+		pkg  string
+		name string
+	}
+
+	var results []*Result
+	byKey := make(map[resultKey]*Result)
+
+	pkg := ""
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "pkg: ") {
+			pkg = strings.TrimSpace(strings.TrimPrefix(line, "pkg: "))
+			continue
+		}
+
+		fields := strings.Fields(line)
+		// Benchmarks that log print their name on a line of it's own, which we can
+		// skip.
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+
+		run, err := parseResultLine(fields)
+		if err != nil {
+			return nil, xerrors.Errorf("line %v: %w", lineNumber, err)
+		}
+		run.Package = pkg
+
+		key := resultKey{pkg: pkg, name: run.Name}
+		result, ok := byKey[key]
+		if !ok {
+			byKey[key] = run
+			results = append(results, run)
+			continue
+		}
+
+		// Keep a running average.
+		total := float64(result.Runs + 1)
+		result.NsPerOp += (run.NsPerOp - result.NsPerOp) / total
+		result.BytesPerOp += (run.BytesPerOp - result.BytesPerOp) / total
+		result.AllocsPerOp += (run.AllocsPerOp - result.AllocsPerOp) / total
+		result.Runs++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("error reading benchmark output: %w", err)
+	}
+
+	return results, nil
+}
+
+// Parses a single result line, such as:
+//
+//	BenchmarkPredict/Empty-8   20   865123 ns/op   205980 B/op   2182 allocs/op
+func parseResultLine(fields []string) (*Result, error) {
+	result := &Result{
+		Name: procsSuffix.ReplaceAllString(fields[0], ""),
+		Runs: 1,
+	}
+
+	if _, err := strconv.Atoi(fields[1]); err != nil {
+		return nil, xerrors.Errorf(
+			"iterations %q: %w", fields[1], errs.ErrInvalidBenchmarkOutput,
+		)
+	}
+
+	// The rest of the line is value / unit pairs.
+	for i := 2; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, xerrors.Errorf(
+				"value %q: %w", fields[i], errs.ErrInvalidBenchmarkOutput,
+			)
+		}
+
+		switch fields[i+1] {
+		case "ns/op":
+			result.NsPerOp = value
+		case "B/op":
+			result.BytesPerOp = value
+		case "allocs/op":
+			result.AllocsPerOp = value
+		}
+	}
+
+	return result, nil
+}
+
+// Thresholds are how much worse a benchmark can get before it is flagged as a
+// regression, as a fraction of the old value.
+type Thresholds struct {
+	Time   float64
+	Bytes  float64
+	Allocs float64
+}
+
+// DefaultThresholds allow some noise in timings, but expect memory use to be stable.
+var DefaultThresholds = Thresholds{
+	Time:   0.10,
+	Bytes:  0.05,
+	Allocs: 0.02,
+}
+
+// Comparison compares a benchmark between two runs.
+type Comparison struct {
+	Package string
+	Name    string
+
+	// The benchmark from the old run. nil if it is a new benchmark.
+	Old *Result
+	New *Result
+
+	// The change of each measurement, as a fraction of the old value. A positive
+	// change means the benchmark got worse.
+	TimeChange   float64
+	BytesChange  float64
+	AllocsChange float64
+
+	// Whether any change is over its threshold.
+	Regression bool
+}
+
+// Returns the change from old to new as a fraction of old.
+func fractionChange(oldValue float64, newValue float64) float64 {
+	if oldValue == 0 {
+		if newValue == 0 {
+			return 0
+		}
+		return 1
+	}
+	return (newValue - oldValue) / oldValue
+}
+
+// Compare compares every benchmark of a new run with the same benchmark of an old one,
+// in the order of the new run. Benchmarks that were removed are left out.
+func Compare(
+	oldResults []*Result, newResults []*Result, thresholds Thresholds,
+) []*Comparison {
+	type resultKey struct {
+		pkg  string
+		name string
+	}
+
+	oldByKey := make(map[resultKey]*Result, len(oldResults))
+	for _, result := range oldResults {
+		oldByKey[resultKey{pkg: result.Package, name: result.Name}] = result
+	}
+
+	comparisons := make([]*Comparison, 0, len(newResults))
+	for _, newResult := range newResults {
+		comparison := &Comparison{
+			Package: newResult.Package,
+			Name:    newResult.Name,
+			New:     newResult,
+		}
+		comparisons = append(comparisons, comparison)
+
+		oldResult, ok := oldByKey[resultKey{pkg: newResult.Package, name: newResult.Name}]
+		if !ok {
+			continue
+		}
+		comparison.Old = oldResult
+
+		comparison.TimeChange = fractionChange(oldResult.NsPerOp, newResult.NsPerOp)
+		comparison.BytesChange = fractionChange(
+			oldResult.BytesPerOp, newResult.BytesPerOp,
+		)
+		comparison.AllocsChange = fractionChange(
+			oldResult.AllocsPerOp, newResult.AllocsPerOp,
+		)
+
+		comparison.Regression = comparison.TimeChange > thresholds.Time ||
+			comparison.BytesChange > thresholds.Bytes ||
+			comparison.AllocsChange > thresholds.Allocs
+	}
+
+	return comparisons
+}
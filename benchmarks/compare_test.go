@@ -0,0 +1,100 @@
+package benchmarks
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"strings"
+	"testing"
+)
+
+const oldOutput = `goos: linux
+goarch: amd64
+pkg: github.com/peake100/turnup-go/models
+BenchmarkCalculateChances/Empty-8     100   36000 ns/op   11000 B/op   9 allocs/op
+BenchmarkCalculateChances/Empty-8     100   38000 ns/op   11000 B/op   9 allocs/op
+BenchmarkPhaseGeneration/Empty-8      100  200000 ns/op  159000 B/op  842 allocs/op
+BenchmarkRemoved-8                    100     100 ns/op
+PASS
+ok  	github.com/peake100/turnup-go/models	1.000s
+`
+
+const newOutput = `pkg: github.com/peake100/turnup-go/models
+BenchmarkCalculateChances/Empty-4     100   37500 ns/op   11000 B/op   9 allocs/op
+BenchmarkPhaseGeneration/Empty-4      100  200000 ns/op  159000 B/op  900 allocs/op
+BenchmarkLogs
+    benchmark_test.go:10: some log
+BenchmarkLogs-4                       100      50 ns/op
+PASS
+`
+
+func TestParseResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results, err := ParseResults(strings.NewReader(oldOutput))
+	if !assert.NoError(err) || !assert.Len(results, 3) {
+		t.FailNow()
+	}
+
+	chances := results[0]
+	assert.Equal("github.com/peake100/turnup-go/models", chances.Package)
+	assert.Equal("BenchmarkCalculateChances/Empty", chances.Name)
+	assert.Equal(2, chances.Runs)
+	assert.Equal(37000.0, chances.NsPerOp)
+	assert.Equal(11000.0, chances.BytesPerOp)
+	assert.Equal(9.0, chances.AllocsPerOp)
+
+	// Without -benchmem there are no bytes or allocations.
+	removed := results[2]
+	assert.Equal("BenchmarkRemoved", removed.Name)
+	assert.Equal(100.0, removed.NsPerOp)
+	assert.Zero(removed.AllocsPerOp)
+}
+
+func TestParseResultsInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseResults(strings.NewReader("BenchmarkBad-8  100  fast ns/op\n"))
+	assert.True(xerrors.Is(err, errs.ErrInvalidBenchmarkOutput))
+
+	_, err = ParseResults(strings.NewReader("BenchmarkBad-8  many  10 ns/op\n"))
+	assert.True(xerrors.Is(err, errs.ErrInvalidBenchmarkOutput))
+}
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	oldResults, err := ParseResults(strings.NewReader(oldOutput))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	newResults, err := ParseResults(strings.NewReader(newOutput))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	comparisons := Compare(oldResults, newResults, DefaultThresholds)
+	if !assert.Len(comparisons, 3) {
+		t.FailNow()
+	}
+
+	// A little slower, but within the noise we allow for.
+	chances := comparisons[0]
+	assert.Same(oldResults[0], chances.Old)
+	assert.InDelta(0.0135, chances.TimeChange, 0.0001)
+	assert.False(chances.Regression)
+
+	// More allocations is always a regression.
+	generation := comparisons[1]
+	assert.InDelta(0.0689, generation.AllocsChange, 0.0001)
+	assert.True(generation.Regression)
+
+	// New benchmarks have nothing to compare against.
+	logs := comparisons[2]
+	assert.Equal("BenchmarkLogs", logs.Name)
+	assert.Nil(logs.Old)
+	assert.False(logs.Regression)
+}
@@ -0,0 +1,312 @@
+// Package calibration measures how well predictions match what actually happened, so
+// we can tell whether the chances the predictor reports can be taken at face value.
+package calibration
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/values"
+	"golang.org/x/xerrors"
+	"math"
+)
+
+// The number of in-game patterns, which are the patterns we report on.
+const patternCount = 4
+
+// Chances are rounded to 4 digits, so a pattern that did happen can be reported with
+// a chance of 0. We use this as the lowest chance when working out the log loss so a
+// single bad prediction does not make it infinite.
+const minLogLossChance = 0.0001
+
+// Outcome is a ticker along with what actually happened on the island that week.
+type Outcome struct {
+	// The ticker with the information known when the prediction is made.
+	Ticker *models.PriceTicker
+	// The pattern the week turned out to be.
+	Pattern models.PricePattern
+	// The prices for the whole week. A price of 0 means it is not known. The spike
+	// peak is only checked for weeks with every price, and heat only for weeks with
+	// every price from the ticker's current period on.
+	Prices models.NookPriceArray
+}
+
+// ConfusionTable counts outcomes by the pattern that happened (the first index) and
+// the pattern that was predicted to be the most likely (the second index).
+type ConfusionTable [patternCount][patternCount]int
+
+// The fraction of outcomes where the most likely pattern is the one that happened.
+func (table *ConfusionTable) Accuracy() float64 {
+	var correct, total int
+	for actual, row := range table {
+		for predicted, count := range row {
+			total += count
+			if actual == predicted {
+				correct += count
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return float64(correct) / float64(total)
+}
+
+// SpikeReport is how well the predicted spike chances matched reality.
+type SpikeReport struct {
+	// The chance of any spike, a big spike and a small spike.
+	Any   *Reliability
+	Big   *Reliability
+	Small *Reliability
+	// The chance of the spike peaking on each price period, from PeakBreakdown().
+	Peak *Reliability
+}
+
+// SkippedOutcome is an outcome no prediction could be made for.
+type SkippedOutcome struct {
+	// The index of the outcome in the outcomes passed in.
+	Index   int
+	Outcome *Outcome
+	// Why the prediction failed, like errs.ErrImpossibleTickerPrices.
+	Err error
+}
+
+// Report is how well the predictions for a set of outcomes matched reality.
+type Report struct {
+	// The number of outcomes evaluated. Skipped outcomes are not counted.
+	Count int
+	// The outcomes no prediction could be made for, like those with a typo in their
+	// prices. They are left out of everything else in the report.
+	Skipped []SkippedOutcome
+
+	// Every pattern chance of every prediction.
+	Reliability *Reliability
+	// The pattern chances for each pattern on it's own.
+	PatternReliability [patternCount]*Reliability
+
+	// The average negative log of the chance given to the pattern that happened.
+	// Lower is better.
+	LogLoss float64
+	// The average sum of squared differences between each pattern's chance and whether
+	// it happened. 0 is perfect, and lower is better.
+	Brier float64
+
+	// Predicted most-likely pattern against the actual pattern.
+	Confusion ConfusionTable
+
+	// How well the spike chances matched reality.
+	Spikes SpikeReport
+
+	// The correlation between the heat of each prediction and the best price from the
+	// ticker's current period on. NaN if there are less than 2 outcomes with those
+	// prices, or either value never changes.
+	HeatCorrelation float64
+	// The number of outcomes used for HeatCorrelation.
+	HeatCount int
+}
+
+func newReport() *Report {
+	report := &Report{
+		Reliability: newReliability(),
+		Spikes: SpikeReport{
+			Any:   newReliability(),
+			Big:   newReliability(),
+			Small: newReliability(),
+			Peak:  newReliability(),
+		},
+	}
+	for i := range report.PatternReliability {
+		report.PatternReliability[i] = newReliability()
+	}
+	return report
+}
+
+// Evaluate makes a prediction for every outcome and reports how well they matched
+// what happened. Outcomes that can't be predicted are added to Report.Skipped, and
+// the rest are still evaluated. Returns errs.ErrInvalidOutcome if an outcome has no
+// ticker, or a pattern that is not an in-game pattern.
+func Evaluate(outcomes []*Outcome) (*Report, error) {
+	return EvaluateWithOptions(outcomes, models.PredictOptions{})
+}
+
+// EvaluateWithOptions makes a prediction for every outcome with options and reports
+// how well they matched what happened, the same way as Evaluate.
+func EvaluateWithOptions(
+	outcomes []*Outcome, options models.PredictOptions,
+) (*Report, error) {
+	report := newReport()
+	heat := new(correlation)
+
+	for i, outcome := range outcomes {
+		if outcome.Ticker == nil ||
+			outcome.Pattern < models.FLUCTUATING ||
+			outcome.Pattern > models.SMALLSPIKE {
+			return nil, xerrors.Errorf("outcome %v: %w", i, errs.ErrInvalidOutcome)
+		}
+
+		predictor := &models.Predictor{
+			Ticker:         outcome.Ticker,
+			PredictOptions: options,
+		}
+		prediction, err := predictor.Predict()
+		if err != nil {
+			report.Skipped = append(
+				report.Skipped, SkippedOutcome{Index: i, Outcome: outcome, Err: err},
+			)
+			continue
+		}
+		report.Count++
+
+		report.addPatterns(prediction, outcome)
+		report.addSpikes(prediction, outcome)
+		if bestPrice, ok := outcome.bestFuturePrice(); ok {
+			heat.add(float64(prediction.Heat), float64(bestPrice))
+		}
+	}
+
+	if report.Count > 0 {
+		report.LogLoss /= float64(report.Count)
+		report.Brier /= float64(report.Count)
+	}
+
+	report.Reliability.finalize()
+	for _, reliability := range report.PatternReliability {
+		reliability.finalize()
+	}
+	for _, reliability := range []*Reliability{
+		report.Spikes.Any, report.Spikes.Big, report.Spikes.Small, report.Spikes.Peak,
+	} {
+		reliability.finalize()
+	}
+
+	report.HeatCorrelation = heat.value()
+	report.HeatCount = heat.count
+
+	return report, nil
+}
+
+func (report *Report) addPatterns(prediction *models.Prediction, outcome *Outcome) {
+	mostLikely := models.FLUCTUATING
+	mostLikelyChance := -1.0
+
+	for _, potentialPattern := range prediction.Patterns {
+		pattern := potentialPattern.Pattern
+		// Custom pattern registries may have patterns we can't report on.
+		if pattern < models.FLUCTUATING || pattern > models.SMALLSPIKE {
+			continue
+		}
+
+		chance := potentialPattern.Chance()
+		happened := pattern == outcome.Pattern
+
+		report.Reliability.add(chance, happened)
+		report.PatternReliability[pattern].add(chance, happened)
+
+		var actual float64
+		if happened {
+			actual = 1
+			report.LogLoss -= math.Log(math.Max(chance, minLogLossChance))
+		}
+		report.Brier += math.Pow(chance-actual, 2)
+
+		if chance > mostLikelyChance {
+			mostLikely = pattern
+			mostLikelyChance = chance
+		}
+	}
+
+	report.Confusion[outcome.Pattern][mostLikely]++
+}
+
+func (report *Report) addSpikes(prediction *models.Prediction, outcome *Outcome) {
+	spikes := prediction.Spikes
+	isBig := outcome.Pattern == models.BIGSPIKE
+	isSmall := outcome.Pattern == models.SMALLSPIKE
+
+	report.Spikes.Any.add(spikes.Any().Chance(), isBig || isSmall)
+	report.Spikes.Big.add(spikes.Big().Chance(), isBig)
+	report.Spikes.Small.add(spikes.Small().Chance(), isSmall)
+
+	// We need every price to know where the spike peaked.
+	if !outcome.allPricesKnown(0) {
+		return
+	}
+
+	peakPeriod := models.PricePeriod(-1)
+	if isBig || isSmall {
+		peakPeriod = outcome.highestPricePeriod()
+	}
+
+	for period, chance := range spikes.Any().PeakBreakdown() {
+		report.Spikes.Peak.add(chance, models.PricePeriod(period) == peakPeriod)
+	}
+}
+
+// Returns true if every price from ``start`` on is known.
+func (outcome *Outcome) allPricesKnown(start models.PricePeriod) bool {
+	for period := start; period < values.PricePeriodCount; period++ {
+		if outcome.Prices[period] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the period with the highest price of the week. The peak of a spike is always
+// higher than the prices around it.
+func (outcome *Outcome) highestPricePeriod() models.PricePeriod {
+	highest := models.PricePeriod(0)
+	for period, price := range outcome.Prices {
+		if price > outcome.Prices[highest] {
+			highest = models.PricePeriod(period)
+		}
+	}
+	return highest
+}
+
+// Returns the best price from the ticker's current period to the end of the week, and
+// whether all of those prices are known.
+func (outcome *Outcome) bestFuturePrice() (price int, ok bool) {
+	currentPeriod := outcome.Ticker.CurrentPeriod
+	if !outcome.allPricesKnown(currentPeriod) {
+		return 0, false
+	}
+
+	for period := currentPeriod; period < values.PricePeriodCount; period++ {
+		if outcome.Prices[period] > price {
+			price = outcome.Prices[period]
+		}
+	}
+	return price, true
+}
+
+// Works out the Pearson correlation of pairs of values as they are added.
+type correlation struct {
+	count                           int
+	sumX, sumY, sumXX, sumYY, sumXY float64
+}
+
+func (corr *correlation) add(x float64, y float64) {
+	corr.count++
+	corr.sumX += x
+	corr.sumY += y
+	corr.sumXX += x * x
+	corr.sumYY += y * y
+	corr.sumXY += x * y
+}
+
+func (corr *correlation) value() float64 {
+	if corr.count < 2 {
+		return math.NaN()
+	}
+
+	count := float64(corr.count)
+	covariance := corr.sumXY - corr.sumX*corr.sumY/count
+	varianceX := corr.sumXX - corr.sumX*corr.sumX/count
+	varianceY := corr.sumYY - corr.sumY*corr.sumY/count
+
+	if varianceX <= 0 || varianceY <= 0 {
+		return math.NaN()
+	}
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
@@ -0,0 +1,164 @@
+package calibration
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/internal/gamesim"
+	"github.com/peake100/turnup-go/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"math"
+	"testing"
+)
+
+// Simulates weeks with the game's routine, and makes outcomes that know the
+// purchase price, previous pattern and prices up to wednesday morning.
+func newSimulatedOutcomes(count int) []*Outcome {
+	random := new(gamesim.Random)
+	random.Seed(1)
+
+	outcomes := make([]*Outcome, count)
+	previousPattern := gamesim.Unknown
+	for i := range outcomes {
+		week := gamesim.Simulate(random, previousPattern)
+		previousPattern = week.Pattern
+
+		ticker := models.NewTicker(
+			week.PurchasePrice, models.PricePattern(week.PreviousPattern), 4,
+		)
+		copy(ticker.Prices[:5], week.Prices[:5])
+
+		outcomes[i] = &Outcome{
+			Ticker:  ticker,
+			Pattern: models.PricePattern(week.Pattern),
+			Prices:  week.Prices,
+		}
+	}
+
+	return outcomes
+}
+
+func TestEvaluateSimulated(t *testing.T) {
+	assert := assert.New(t)
+
+	const outcomeCount = 300
+	outcomes := newSimulatedOutcomes(outcomeCount)
+
+	report, err := Evaluate(outcomes)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	assert.Equal(outcomeCount, report.Count)
+
+	// Every pattern chance should land in a bin.
+	var binned int
+	for _, bin := range report.Reliability {
+		binned += bin.Count
+		if bin.Count > 0 {
+			assert.GreaterOrEqual(bin.Predicted, bin.Min)
+			assert.LessOrEqual(bin.Predicted, bin.Max)
+		}
+	}
+	assert.Equal(outcomeCount*4, binned)
+
+	var confused int
+	for _, row := range report.Confusion {
+		for _, count := range row {
+			confused += count
+		}
+	}
+	assert.Equal(outcomeCount, confused)
+
+	// Knowing half the week should be much better than guessing.
+	assert.Greater(report.Confusion.Accuracy(), 0.8)
+	assert.Less(report.Brier, 0.3)
+	assert.Less(report.LogLoss, 0.5)
+	assert.Less(report.Reliability.CalibrationError(), 0.05)
+
+	// Every outcome has all of its prices, so each period's peak chance is checked.
+	var peaks int
+	for _, bin := range report.Spikes.Peak {
+		peaks += bin.Count
+	}
+	assert.Equal(outcomeCount*12, peaks)
+
+	assert.Equal(outcomeCount, report.HeatCount)
+	assert.Greater(report.HeatCorrelation, 0.0)
+}
+
+func TestEvaluateInvalidOutcome(t *testing.T) {
+	assert := assert.New(t)
+
+	outcomes := newSimulatedOutcomes(2)
+	outcomes[1].Pattern = models.UNKNOWN
+
+	_, err := Evaluate(outcomes)
+	assert.True(xerrors.Is(err, errs.ErrInvalidOutcome))
+
+	outcomes[1].Pattern = models.DECREASING
+	outcomes[1].Ticker = nil
+	_, err = Evaluate(outcomes)
+	assert.True(xerrors.Is(err, errs.ErrInvalidOutcome))
+}
+
+func TestEvaluateImpossibleTicker(t *testing.T) {
+	assert := assert.New(t)
+
+	outcomes := newSimulatedOutcomes(3)
+	outcomes[1].Ticker.Prices[0] = 600
+
+	report, err := Evaluate(outcomes)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	assert.Equal(2, report.Count)
+	if assert.Len(report.Skipped, 1) {
+		skipped := report.Skipped[0]
+		assert.Equal(1, skipped.Index)
+		assert.Same(outcomes[1], skipped.Outcome)
+		assert.True(xerrors.Is(skipped.Err, errs.ErrImpossibleTickerPrices))
+	}
+
+	// The skipped outcome is left out of everything else.
+	expected, err := Evaluate([]*Outcome{outcomes[0], outcomes[2]})
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal(expected.Brier, report.Brier)
+	assert.Equal(expected.LogLoss, report.LogLoss)
+	assert.Equal(expected.Confusion, report.Confusion)
+	assert.Equal(expected.Reliability, report.Reliability)
+}
+
+func TestEvaluateNoOutcomes(t *testing.T) {
+	assert := assert.New(t)
+
+	report, err := Evaluate(nil)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	assert.Equal(0, report.Count)
+	assert.Equal(0.0, report.Brier)
+	assert.Equal(0.0, report.Reliability.CalibrationError())
+	assert.True(math.IsNaN(report.HeatCorrelation))
+}
+
+func TestCorrelation(t *testing.T) {
+	assert := assert.New(t)
+
+	corr := new(correlation)
+	for i := 0; i < 10; i++ {
+		corr.add(float64(i), float64(-2*i+3))
+	}
+	assert.InDelta(-1, corr.value(), 0.0000001)
+
+	corr = new(correlation)
+	corr.add(1, 5)
+	corr.add(2, 5)
+	assert.True(math.IsNaN(corr.value()), "no variance")
+}
@@ -0,0 +1,93 @@
+package calibration
+
+// The number of equal-width bins a Reliability splits chances into.
+const ReliabilityBinCount = 10
+
+// ReliabilityBin holds every prediction whose chance fell in a range. In a calibrated
+// model, things predicted with a chance of X% happen about X% of the time, so Observed
+// should be close to Predicted.
+type ReliabilityBin struct {
+	// The range of chances that fall in this bin. Max is inclusive only for the last
+	// bin.
+	Min float64
+	Max float64
+
+	// The number of predicted chances in this bin.
+	Count int
+	// The average predicted chance.
+	Predicted float64
+	// The fraction of the predictions that came true.
+	Observed float64
+
+	predictedTotal float64
+	observedTotal  float64
+}
+
+// Reliability is a reliability diagram: predicted chances grouped into equal-width
+// bins with how often they came true.
+type Reliability [ReliabilityBinCount]*ReliabilityBin
+
+// Returns the bin a chance falls in.
+func (reliability *Reliability) bin(chance float64) *ReliabilityBin {
+	index := int(chance * ReliabilityBinCount)
+	if index >= ReliabilityBinCount {
+		index = ReliabilityBinCount - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	return reliability[index]
+}
+
+// Adds a predicted chance and whether it happened.
+func (reliability *Reliability) add(chance float64, happened bool) {
+	bin := reliability.bin(chance)
+	bin.Count++
+	bin.predictedTotal += chance
+	if happened {
+		bin.observedTotal++
+	}
+}
+
+// Works out the averages once every chance has been added.
+func (reliability *Reliability) finalize() {
+	for _, bin := range reliability {
+		if bin.Count == 0 {
+			continue
+		}
+		bin.Predicted = bin.predictedTotal / float64(bin.Count)
+		bin.Observed = bin.observedTotal / float64(bin.Count)
+	}
+}
+
+// The expected calibration error: the average distance between the predicted and
+// observed chance of each bin, weighted by the number of predictions in the bin. 0 is
+// perfectly calibrated.
+func (reliability *Reliability) CalibrationError() float64 {
+	var total float64
+	var count int
+	for _, bin := range reliability {
+		difference := bin.Predicted - bin.Observed
+		if difference < 0 {
+			difference = -difference
+		}
+		total += difference * float64(bin.Count)
+		count += bin.Count
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+func newReliability() *Reliability {
+	reliability := new(Reliability)
+	for i := range reliability {
+		reliability[i] = &ReliabilityBin{
+			Min: float64(i) / ReliabilityBinCount,
+			Max: float64(i+1) / ReliabilityBinCount,
+		}
+	}
+	return reliability
+}
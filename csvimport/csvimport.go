@@ -0,0 +1,295 @@
+// Package csvimport reads the turnip price histories of many islands from CSV files,
+// such as community spreadsheets, into price tickers.
+//
+// Each row is one week on one island, with the columns:
+//
+//	island, week start, buy price, mon am, mon pm, ... sat pm, pattern
+//
+// The first row is a header and is skipped. Week starts are dates written as
+// 2006-01-02. Unknown prices can be left empty. The pattern column is optional, and
+// can be a pattern name or a Turnip Prophet pattern index. Each row's ticker is checked
+// with PriceTicker.Validate, so a row with a price the game can't make is reported as a
+// RowError rather than failing a prediction later on.
+package csvimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/values"
+	"golang.org/x/xerrors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The layout of the week start column.
+const WeekStartLayout = "2006-01-02"
+
+// Column indexes.
+const (
+	islandColumn     = 0
+	weekStartColumn  = 1
+	buyPriceColumn   = 2
+	firstPriceColumn = 3
+	patternColumn    = firstPriceColumn + values.PricePeriodCount
+)
+
+// Record is a single week on an island.
+type Record struct {
+	// The row of the CSV file the record came from, starting at 1 for the header.
+	Row       int
+	Island    string
+	WeekStart time.Time
+
+	// The ticker for the week. The previous pattern is the pattern of the island's
+	// record for the week before, if it has one, and the current period is the last
+	// period with a price.
+	Ticker *models.PriceTicker
+
+	// The pattern the week turned out to be. UNKNOWN if not given.
+	Pattern models.PricePattern
+}
+
+// RowError is a problem with a single row of a CSV file. The row is left out of the
+// imported records.
+type RowError struct {
+	// The row of the CSV file, starting at 1 for the header.
+	Row int
+	// The name of the column with the problem, or empty if it is with the whole row.
+	Column string
+	Err    error
+}
+
+func (err *RowError) Error() string {
+	if err.Column == "" {
+		return fmt.Sprintf("row %v: %v", err.Row, err.Err)
+	}
+	return fmt.Sprintf("row %v, %v: %v", err.Row, err.Column, err.Err)
+}
+
+func (err *RowError) Unwrap() error {
+	return err.Err
+}
+
+// Result holds the records that were imported, and the errors for any rows that were
+// not.
+type Result struct {
+	Records []*Record
+	Errors  []*RowError
+}
+
+// Returns the name of a column for error messages.
+func columnName(column int) string {
+	switch {
+	case column == islandColumn:
+		return "island"
+	case column == weekStartColumn:
+		return "week start"
+	case column == buyPriceColumn:
+		return "buy price"
+	case column == patternColumn:
+		return "pattern"
+	default:
+		period := models.PricePeriod(column - firstPriceColumn)
+		return fmt.Sprintf("%v %v", period.Weekday(), period.ToD())
+	}
+}
+
+// Read imports every row of a CSV file. Rows with invalid values are reported in the
+// result's Errors rather than stopping the import. An error is only returned if the
+// file itself can't be read.
+func Read(reader io.Reader) (*Result, error) {
+	csvReader := csv.NewReader(reader)
+	// The pattern column is optional, so rows may have different lengths.
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return &Result{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("error reading csv header: %w", err)
+	}
+	if len(header) != patternColumn && len(header) != patternColumn+1 {
+		return nil, errs.ErrInvalidCSVHeader
+	}
+
+	result := new(Result)
+	rowNumber := 1
+	for {
+		fields, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNumber++
+		if err != nil {
+			// A row with bad quoting can be skipped, anything else means we can't
+			// keep reading.
+			var parseErr *csv.ParseError
+			if xerrors.As(err, &parseErr) {
+				result.addError(rowNumber, -1, err)
+				continue
+			}
+			return nil, xerrors.Errorf("error reading csv: %w", err)
+		}
+
+		record, rowErr := parseRow(rowNumber, fields)
+		if rowErr != nil {
+			result.Errors = append(result.Errors, rowErr)
+			continue
+		}
+		result.Records = append(result.Records, record)
+	}
+
+	result.linkWeeks()
+	return result, nil
+}
+
+func (result *Result) addError(rowNumber int, column int, err error) {
+	rowErr := &RowError{Row: rowNumber, Err: err}
+	if column >= 0 {
+		rowErr.Column = columnName(column)
+	}
+	result.Errors = append(result.Errors, rowErr)
+}
+
+// Sets the previous pattern of each record from the island's record for the week
+// before, and reports rows for a week an island already has.
+func (result *Result) linkWeeks() {
+	type weekKey struct {
+		island    string
+		weekStart time.Time
+	}
+
+	weeks := make(map[weekKey]*Record, len(result.Records))
+	records := result.Records[:0]
+	for _, record := range result.Records {
+		key := weekKey{island: record.Island, weekStart: record.WeekStart}
+		if _, ok := weeks[key]; ok {
+			result.addError(record.Row, -1, errs.ErrDuplicateCSVWeek)
+			continue
+		}
+		weeks[key] = record
+		records = append(records, record)
+	}
+	result.Records = records
+
+	for _, record := range result.Records {
+		previousKey := weekKey{
+			island:    record.Island,
+			weekStart: record.WeekStart.AddDate(0, 0, -7),
+		}
+		if previous, ok := weeks[previousKey]; ok {
+			record.Ticker.PreviousPattern = previous.Pattern
+		}
+	}
+}
+
+func invalidValue(rowNumber int, column int, value string) *RowError {
+	return &RowError{
+		Row:    rowNumber,
+		Column: columnName(column),
+		Err:    xerrors.Errorf("%q: %w", value, errs.ErrInvalidCSVValue),
+	}
+}
+
+func parseRow(rowNumber int, fields []string) (*Record, *RowError) {
+	if len(fields) != patternColumn && len(fields) != patternColumn+1 {
+		return nil, &RowError{
+			Row: rowNumber,
+			Err: xerrors.Errorf(
+				"row has %v columns: %w", len(fields), errs.ErrInvalidCSVValue,
+			),
+		}
+	}
+
+	record := &Record{
+		Row:     rowNumber,
+		Island:  strings.TrimSpace(fields[islandColumn]),
+		Pattern: models.UNKNOWN,
+	}
+	if record.Island == "" {
+		return nil, invalidValue(rowNumber, islandColumn, fields[islandColumn])
+	}
+
+	weekStart, err := time.Parse(
+		WeekStartLayout, strings.TrimSpace(fields[weekStartColumn]),
+	)
+	if err != nil {
+		return nil, invalidValue(rowNumber, weekStartColumn, fields[weekStartColumn])
+	}
+	record.WeekStart = weekStart
+
+	buyPrice, ok := parsePrice(fields[buyPriceColumn])
+	if !ok {
+		return nil, invalidValue(rowNumber, buyPriceColumn, fields[buyPriceColumn])
+	}
+
+	record.Ticker = models.NewTicker(buyPrice, models.UNKNOWN, 0)
+	for period := models.PricePeriod(0); period < values.PricePeriodCount; period++ {
+		column := firstPriceColumn + int(period)
+		price, ok := parsePrice(fields[column])
+		if !ok {
+			return nil, invalidValue(rowNumber, column, fields[column])
+		}
+		if price != 0 {
+			record.Ticker.Prices[period] = price
+			record.Ticker.CurrentPeriod = period
+		}
+	}
+
+	if err := record.Ticker.Validate(); err != nil {
+		return nil, tickerRowError(rowNumber, err)
+	}
+
+	if len(fields) > patternColumn {
+		pattern, err := models.PatternFromTurnipProphet(
+			strings.TrimSpace(fields[patternColumn]),
+		)
+		if err != nil {
+			return nil, invalidValue(rowNumber, patternColumn, fields[patternColumn])
+		}
+		record.Pattern = pattern
+	}
+
+	return record, nil
+}
+
+// Reports a ticker that failed validation, such as one with a price over what the
+// game can make. A problem with a single field is reported for its column.
+func tickerRowError(rowNumber int, err error) *RowError {
+	var problems errs.TickerErrors
+	if !xerrors.As(err, &problems) || len(problems) != 1 {
+		return &RowError{Row: rowNumber, Err: err}
+	}
+
+	problem := problems[0]
+	rowErr := &RowError{Row: rowNumber, Err: problem.Err}
+	switch problem.Field {
+	case "PurchasePrice":
+		rowErr.Column = columnName(buyPriceColumn)
+	case "Prices":
+		rowErr.Column = columnName(firstPriceColumn + problem.Period)
+	default:
+		rowErr.Err = err
+	}
+	return rowErr
+}
+
+// Parses a price, returning 0 for an empty value.
+func parsePrice(value string) (price int, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, true
+	}
+
+	price, err := strconv.Atoi(value)
+	if err != nil || price < 0 {
+		return 0, false
+	}
+	return price, true
+}
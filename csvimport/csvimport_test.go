@@ -0,0 +1,149 @@
+package csvimport
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const header = "island,week,buy,mon am,mon pm,tue am,tue pm,wed am,wed pm," +
+	"thu am,thu pm,fri am,fri pm,sat am,sat pm,pattern\n"
+
+func TestRead(t *testing.T) {
+	assert := assert.New(t)
+
+	data := header +
+		"Nook,2020-04-05,100,86,82,78,74,70,66,62,58,54,50,46,42,decreasing\n" +
+		"Nook,2020-04-12,98,86,82,,,,,,,,,,,\n" +
+		"Tom,2020-04-12,,110,,,,,,,,,,,,1\n" +
+		"Tom,2020-04-19,104,90,85,,,,,,,,,,\n"
+
+	result, err := Read(strings.NewReader(data))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Empty(result.Errors)
+	if !assert.Len(result.Records, 4) {
+		t.FailNow()
+	}
+
+	first := result.Records[0]
+	assert.Equal(2, first.Row)
+	assert.Equal("Nook", first.Island)
+	assert.Equal(time.Date(2020, 4, 5, 0, 0, 0, 0, time.UTC), first.WeekStart)
+	assert.Equal(100, first.Ticker.PurchasePrice)
+	assert.Equal(models.DECREASING, first.Pattern)
+	assert.Equal(models.UNKNOWN, first.Ticker.PreviousPattern)
+	assert.Equal(models.PricePeriod(11), first.Ticker.CurrentPeriod)
+
+	// The previous pattern comes from the island's week before.
+	second := result.Records[1]
+	assert.Equal(models.DECREASING, second.Ticker.PreviousPattern)
+	assert.Equal(models.UNKNOWN, second.Pattern)
+	assert.Equal(models.PricePeriod(1), second.Ticker.CurrentPeriod)
+	assert.Equal(0, second.Ticker.Prices[2])
+
+	third := result.Records[2]
+	assert.Equal(0, third.Ticker.PurchasePrice)
+	assert.Equal(models.BIGSPIKE, third.Pattern)
+
+	// The pattern column can be left off.
+	fourth := result.Records[3]
+	assert.Equal(models.BIGSPIKE, fourth.Ticker.PreviousPattern)
+	assert.Equal(models.UNKNOWN, fourth.Pattern)
+}
+
+func TestReadRowErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	data := header +
+		",2020-04-05,100,86,,,,,,,,,,,\n" +
+		"Nook,04/05/2020,100,86,,,,,,,,,,,\n" +
+		"Nook,2020-04-05,120,86,,,,,,,,,,,\n" +
+		"Nook,2020-04-05,100,86,-1,,,,,,,,,,\n" +
+		"Nook,2020-04-05,100,86,ok,,,,,,,,,,\n" +
+		"Nook,2020-04-05,100,86,,,,,,,,,,,,spiky\n" +
+		"Nook,2020-04-05,100,86\n" +
+		"Nook,2020-04-05,100,86,,,,,,,,,,,\n" +
+		"Nook,2020-04-05,100,90,,,,,,,,,,,\n" +
+		"Nook,2020-04-12,100,86,700,,,,,,,,,,\n"
+
+	result, err := Read(strings.NewReader(data))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Only the first good row for the week is kept.
+	if assert.Len(result.Records, 1) {
+		assert.Equal(9, result.Records[0].Row)
+	}
+
+	expected := []struct {
+		Row    int
+		Column string
+		Err    error
+	}{
+		{2, "island", errs.ErrInvalidCSVValue},
+		{3, "week start", errs.ErrInvalidCSVValue},
+		{4, "buy price", errs.ErrInvalidPurchasePrice},
+		{5, "Monday PM", errs.ErrInvalidCSVValue},
+		{6, "Monday PM", errs.ErrInvalidCSVValue},
+		{7, "pattern", errs.ErrInvalidCSVValue},
+		{8, "", errs.ErrInvalidCSVValue},
+		{11, "Monday PM", errs.ErrInvalidTickerPrice},
+		{10, "", errs.ErrDuplicateCSVWeek},
+	}
+
+	if !assert.Len(result.Errors, len(expected)) {
+		t.FailNow()
+	}
+	for i, rowErr := range result.Errors {
+		assert.Equal(expected[i].Row, rowErr.Row, "error %v", i)
+		assert.Equal(expected[i].Column, rowErr.Column, "error %v", i)
+		assert.True(xerrors.Is(rowErr, expected[i].Err), "error %v", i)
+	}
+}
+
+func TestReadBadHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Read(strings.NewReader("island,week,buy\n"))
+	assert.True(xerrors.Is(err, errs.ErrInvalidCSVHeader))
+
+	result, err := Read(strings.NewReader(""))
+	assert.NoError(err)
+	assert.Empty(result.Records)
+}
+
+func TestResultPredict(t *testing.T) {
+	assert := assert.New(t)
+
+	data := header +
+		"Nook,2020-04-05,100,86,82,78,74,70,66,62,58,54,50,46,42,decreasing\n" +
+		"Tom,2020-04-05,100,86,82,200,,,,,,,,,\n"
+
+	result, err := Read(strings.NewReader(data))
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	predictions := result.Predict(models.PredictOptions{})
+	if !assert.Len(predictions, 2) {
+		t.FailNow()
+	}
+
+	assert.Equal(result.Records[0], predictions[0].Record)
+	assert.NoError(predictions[0].Err)
+	assert.Equal(1.0, predictions[0].Prediction.Patterns[models.DECREASING].Chance())
+
+	assert.Equal(result.Records[1], predictions[1].Record)
+	assert.True(xerrors.Is(predictions[1].Err, errs.ErrImpossibleTickerPrices))
+	assert.Nil(predictions[1].Prediction)
+}
@@ -0,0 +1,34 @@
+package csvimport
+
+import (
+	"github.com/peake100/turnup-go/models"
+)
+
+// RecordPrediction is the prediction for an imported record.
+type RecordPrediction struct {
+	Record     *Record
+	Prediction *models.Prediction
+	// Set if the prediction could not be made, for instance because the record's
+	// prices are impossible.
+	Err error
+}
+
+// Predict makes a prediction for every imported record, in the same order as
+// Records. A record that can't be predicted has it's error set rather than stopping
+// the rest. Records are predicted together with models.PredictBatch.
+func (result *Result) Predict(options models.PredictOptions) []*RecordPrediction {
+	tickers := make([]*models.PriceTicker, len(result.Records))
+	for i, record := range result.Records {
+		tickers[i] = record.Ticker
+	}
+
+	predictions := make([]*RecordPrediction, len(result.Records))
+	for i, batchResult := range models.PredictBatch(tickers, options) {
+		predictions[i] = &RecordPrediction{
+			Record:     result.Records[i],
+			Prediction: batchResult.Prediction,
+			Err:        batchResult.Err,
+		}
+	}
+	return predictions
+}
@@ -21,3 +21,56 @@ var ErrNoSundayPricePeriod = errors.New("there are no price periods on sunday")
 var ErrImpossibleTickerPrices = errors.New(
 	"could not generate possibilities because ticker prices are impossible",
 )
+
+var ErrInvalidPatternDefinition = errors.New("pattern definition is invalid")
+
+var ErrPatternAlreadyRegistered = errors.New(
+	"a definition for this pattern is already registered",
+)
+
+var ErrPatternNotRegistered = errors.New("no definition is registered for pattern")
+
+var ErrNegativePatternChance = errors.New("pattern chances cannot be negative")
+
+var ErrNonFinitePatternChance = errors.New(
+	"pattern chances cannot be infinite or NaN",
+)
+
+var ErrNoPatternChance = errors.New(
+	"no pattern has a chance of occurring after the ticker's previous pattern",
+)
+
+var ErrConstraintsExcludeAll = errors.New(
+	"prediction constraints exclude every potential week that fits the ticker prices",
+)
+
+var ErrInvalidPriceInterval = errors.New(
+	"price interval must be for a valid price period and have a low price at or " +
+		"below its high price",
+)
+
+var ErrInvalidErrorRate = errors.New("error rate must be at least 0 and less than 1")
+
+var ErrInvalidOutcome = errors.New(
+	"outcome must have a ticker and be one of the four in-game patterns",
+)
+
+var ErrInvalidTurnipProphetURL = errors.New("could not parse turnip prophet link")
+
+var ErrTurnipProphetFirstBuy = errors.New(
+	"first-time buyer links are not supported: the game always picks a small spike " +
+		"pattern for a player's first week",
+)
+
+var ErrInvalidCSVHeader = errors.New(
+	"csv must start with a header row of island, week start, buy price, 12 prices " +
+		"and an optional pattern",
+)
+
+var ErrInvalidCSVValue = errors.New("invalid csv value")
+
+var ErrDuplicateCSVWeek = errors.New("island already has a row for this week")
+
+var ErrInvalidBenchmarkOutput = errors.New("could not parse benchmark output")
+
+var ErrInvalidCacheSize = errors.New("prediction cache must hold at least 1 prediction")
@@ -0,0 +1,15 @@
+package errs
+
+import (
+	"errors"
+)
+
+var ErrUnknownLanguage = errors.New("no message catalog for language")
+
+var ErrPhaseStringValue = errors.New("could not parse phase from string")
+
+var ErrWeekdayStringValue = errors.New("could not parse weekday from string")
+
+var ErrTimeOfDayStringValue = errors.New("could not parse time of day from string")
+
+var ErrPricePeriodStringValue = errors.New("could not parse price period from string")
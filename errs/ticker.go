@@ -0,0 +1,80 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/xerrors"
+	"strings"
+)
+
+var ErrInvalidTicker = errors.New("invalid price ticker")
+
+var ErrInvalidPurchasePrice = errors.New(
+	"purchase price must be 0 for unknown, or from 90 to 110 bells",
+)
+
+var ErrInvalidPreviousPattern = errors.New("previous pattern must be 0-4")
+
+var ErrInvalidCurrentPeriod = errors.New("current period must be 0-11")
+
+var ErrInvalidTickerPrice = errors.New(
+	"price must be 0 for unknown, or from 1 to 660 bells",
+)
+
+var ErrPriceAfterCurrentPeriod = errors.New(
+	"prices can't be known for periods after the current period",
+)
+
+// TickerFieldError is a problem with a single field of a price ticker.
+type TickerFieldError struct {
+	// The name of the field, such as "PurchasePrice" or "Prices".
+	Field string
+	// The price period of the problem for Prices and Intervals, or -1.
+	Period int
+	Err    error
+}
+
+func (err *TickerFieldError) Error() string {
+	if err.Period < 0 {
+		return fmt.Sprintf("%v: %v", err.Field, err.Err)
+	}
+	return fmt.Sprintf("%v[%v]: %v", err.Field, err.Period, err.Err)
+}
+
+func (err *TickerFieldError) Unwrap() error {
+	return err.Err
+}
+
+// TickerErrors holds every problem found with a price ticker. It matches
+// ErrInvalidTicker, and any error one of its field errors matches, with xerrors.Is.
+type TickerErrors []*TickerFieldError
+
+func (tickerErrs TickerErrors) Error() string {
+	messages := make([]string, len(tickerErrs))
+	for i, err := range tickerErrs {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%v: %v", ErrInvalidTicker, strings.Join(messages, "; "))
+}
+
+func (tickerErrs TickerErrors) Is(target error) bool {
+	if target == ErrInvalidTicker {
+		return true
+	}
+	for _, err := range tickerErrs {
+		if xerrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first field error that matches ``target``.
+func (tickerErrs TickerErrors) As(target interface{}) bool {
+	for _, err := range tickerErrs {
+		if xerrors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
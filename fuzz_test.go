@@ -0,0 +1,325 @@
+package turnup
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+// These targets run their seed corpus as part of the normal tests. To fuzz them:
+//
+//	go test -run xxx -fuzz FuzzPredict$ -fuzztime 1m .
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/values"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"math"
+	"testing"
+)
+
+// The highest price a fuzzed ticker can have. Prices are wrapped into 0 to this.
+const fuzzMaxPrice = 660
+
+// Makes a ticker from fuzzed values. Prices are 2 bytes each, wrapped into 0-660. Any
+// for periods after the current period are dropped, as they would only make the ticker
+// invalid.
+func fuzzTicker(
+	purchase int, previous int, current int, prices []byte,
+) *models.PriceTicker {
+	ticker := NewPriceTicker(
+		purchase, models.PricePattern(previous), models.PricePeriod(current),
+	)
+	for period := 0; period <= current && period < values.PricePeriodCount; period++ {
+		if len(prices) < (period+1)*2 {
+			break
+		}
+		price := binary.LittleEndian.Uint16(prices[period*2:])
+		ticker.Prices[period] = int(price) % (fuzzMaxPrice + 1)
+	}
+	return ticker
+}
+
+// Turns a ticker's prices into the bytes fuzzTicker expects.
+func fuzzPrices(ticker *models.PriceTicker) []byte {
+	prices := make([]byte, values.PricePeriodCount*2)
+	for period, price := range ticker.Prices {
+		binary.LittleEndian.PutUint16(prices[period*2:], uint16(price))
+	}
+	return prices
+}
+
+// Returns the tickers from our hand-checked prediction tests and the models benchmark
+// fixtures, to start the fuzzer off with.
+func fuzzSeedTickers() []*models.PriceTicker {
+	return []*models.PriceTicker{
+		// Test100BellPurchase
+		NewPriceTicker(100, models.UNKNOWN, 0),
+		// TestUnknownBellPurchase
+		NewPriceTicker(0, models.UNKNOWN, 0),
+		// TestImpossiblePattern
+		{PurchasePrice: 0, PreviousPattern: models.UNKNOWN, Prices: [12]int{10}},
+		// Test100BellPurchaseBigSpike
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.UNKNOWN,
+			CurrentPeriod:   2,
+			Prices:          [12]int{86, 90, 160},
+		},
+		// Test100BellPurchaseFluctuating
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.DECREASING,
+			CurrentPeriod:   5,
+			Prices:          [12]int{140, 140, 140, 140, 140, 140},
+		},
+		// Test100BellPurchaseDecreasing
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.DECREASING,
+			CurrentPeriod:   7,
+			Prices:          [12]int{86, 82, 78, 74, 70, 66, 62, 58},
+		},
+		// Test100BellPurchaseSmallSpike
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.SMALLSPIKE,
+			CurrentPeriod:   2,
+			Prices:          [12]int{120, 120, 199},
+		},
+		// TestMultiplePossibleMatches
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.DECREASING,
+			CurrentPeriod:   1,
+			Prices:          [12]int{86, 82},
+		},
+		// Test100BellPurchaseUnlikelyLowerBoundPattern
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.SMALLSPIKE,
+			CurrentPeriod:   11,
+			Prices:          [12]int{85, 80, 75, 70, 65, 60, 55, 50, 45, 40, 35, 30},
+		},
+		// Test100BellPurchaseUnlikelyUpperBoundPattern
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.SMALLSPIKE,
+			CurrentPeriod:   11,
+			Prices:          [12]int{90, 87, 84, 82, 79, 76, 73, 70, 67, 64, 61, 58},
+		},
+		// TestPredictWithTransitions
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.FLUCTUATING,
+			CurrentPeriod:   1,
+			Prices:          [12]int{86, 82},
+		},
+		// UnknownPurchase fixture
+		{
+			PurchasePrice:   0,
+			PreviousPattern: models.FLUCTUATING,
+			CurrentPeriod:   1,
+			Prices:          [12]int{86, 82},
+		},
+		// PartialWeek fixture
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.DECREASING,
+			CurrentPeriod:   5,
+			Prices:          [12]int{86, 82, 78, 74, 70, 126},
+		},
+		// FullWeek fixture
+		{
+			PurchasePrice:   100,
+			PreviousPattern: models.SMALLSPIKE,
+			CurrentPeriod:   11,
+			Prices:          [12]int{86, 82, 78, 74, 125, 180, 480, 190, 135, 73, 60, 48},
+		},
+		// NearImpossible fixture
+		{
+			PurchasePrice:   90,
+			PreviousPattern: models.BIGSPIKE,
+			CurrentPeriod:   6,
+			Prices:          [12]int{36, 34, 31, 29, 27, 24, 22},
+		},
+	}
+}
+
+func addFuzzSeeds(f *testing.F, errorRates ...float64) {
+	for _, ticker := range fuzzSeedTickers() {
+		args := []interface{}{
+			ticker.PurchasePrice,
+			int(ticker.PreviousPattern),
+			int(ticker.CurrentPeriod),
+			fuzzPrices(ticker),
+		}
+		if len(errorRates) == 0 {
+			f.Add(args...)
+			continue
+		}
+		for _, errorRate := range errorRates {
+			f.Add(append(args, errorRate)...)
+		}
+	}
+}
+
+// A little room for float rounding when checking chances.
+const fuzzTolerance = 1e-9
+
+// Chances are rounded to 4 digits, so each can be off by this much.
+const chanceRounding = 0.00005
+
+// The furthest a sum of ``count`` rounded chances can be from the exact total.
+func chanceSumTolerance(count int) float64 {
+	return float64(count)*chanceRounding + fuzzTolerance
+}
+
+func assertChance(t *testing.T, chance float64, name string) {
+	assert.True(
+		t,
+		chance >= 0 && chance <= 1+fuzzTolerance,
+		"%v chance of %v is not from 0 to 1", name, chance,
+	)
+}
+
+// Checks that a price series is in order, and it's future series sits inside of it.
+// The future series always uses the current price, so if the current price may be a
+// mistake, ``mistakes`` should be true to skip that check.
+func assertPriceRanges(
+	t *testing.T,
+	series *models.PriceSeries,
+	future *models.PriceSeries,
+	mistakes bool,
+	name string,
+) {
+	assert := assert.New(t)
+
+	assert.LessOrEqual(series.MinPrice(), series.GuaranteedPrice(), name)
+	assert.LessOrEqual(series.GuaranteedPrice(), series.MaxPrice(), name)
+
+	if future.MaxPrice() == 0 || mistakes {
+		// There are no future prices to check.
+		return
+	}
+	assert.LessOrEqual(future.GuaranteedPrice(), future.MaxPrice(), name+" future")
+	assert.GreaterOrEqual(future.MinPrice(), series.MinPrice(), name+" future min")
+	assert.LessOrEqual(future.MaxPrice(), series.MaxPrice(), name+" future max")
+}
+
+func assertSpikeChances(t *testing.T, spike models.HasSpikeChance, name string) {
+	assertChance(t, spike.Chance(), name+" spike")
+	for period, chance := range spike.Breakdown() {
+		assertChance(t, chance, fmt.Sprintf("%v spike period %v", name, period))
+	}
+	for period, chance := range spike.PeakBreakdown() {
+		assertChance(t, chance, fmt.Sprintf("%v spike peak period %v", name, period))
+	}
+}
+
+// Checks the things that should be true of any prediction. If ``mistakes`` is false,
+// every known price should be possible in every potential week.
+func assertPredictionInvariants(
+	t *testing.T, ticker *models.PriceTicker, prediction *Prediction, mistakes bool,
+) {
+	assert := assert.New(t)
+
+	assertPriceRanges(
+		t, &prediction.PriceSeries, &prediction.Future, mistakes, "prediction",
+	)
+	assertSpikeChances(t, prediction.Spikes.Any(), "any")
+	assertSpikeChances(t, prediction.Spikes.Big(), "big")
+	assertSpikeChances(t, prediction.Spikes.Small(), "small")
+	for period, chance := range prediction.MistakeChances {
+		assertChance(t, chance, fmt.Sprintf("mistake period %v", period))
+	}
+
+	patternTotal := 0.0
+	for _, pattern := range prediction.Patterns {
+		assertChance(t, pattern.Chance(), pattern.Pattern.String())
+		patternTotal += pattern.Chance()
+
+		if len(pattern.PotentialWeeks) == 0 {
+			continue
+		}
+		assertPriceRanges(
+			t,
+			&pattern.PriceSeries,
+			&pattern.Future,
+			mistakes,
+			pattern.Pattern.String(),
+		)
+
+		for _, week := range pattern.PotentialWeeks {
+			assertChance(t, week.Chance(), "week")
+			assertPriceRanges(t, &week.PriceSeries, &week.Future, mistakes, "week")
+
+			if mistakes {
+				continue
+			}
+			for period, price := range ticker.Prices {
+				if price == 0 {
+					continue
+				}
+				potential := week.Prices[period]
+				assert.True(
+					price >= potential.MinPrice() && price <= potential.MaxPrice(),
+					"price %v for period %v is outside of week bracket %v-%v",
+					price, period, potential.MinPrice(), potential.MaxPrice(),
+				)
+			}
+		}
+	}
+	assert.InDelta(
+		1, patternTotal, chanceSumTolerance(len(prediction.Patterns)), "pattern chances",
+	)
+}
+
+func FuzzPredict(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(
+		t *testing.T, purchase int, previous int, current int, prices []byte,
+	) {
+		ticker := fuzzTicker(purchase, previous, current, prices)
+		prediction, err := Predict(ticker)
+		if xerrors.Is(err, errs.ErrInvalidTicker) ||
+			xerrors.Is(err, errs.ErrImpossibleTickerPrices) {
+			return
+		}
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assertPredictionInvariants(t, ticker, prediction, false)
+	})
+}
+
+func FuzzPredictErrorRate(f *testing.F) {
+	addFuzzSeeds(f, 0.01, 0.2)
+
+	f.Fuzz(func(
+		t *testing.T,
+		purchase int,
+		previous int,
+		current int,
+		prices []byte,
+		errorRate float64,
+	) {
+		if math.IsNaN(errorRate) || errorRate <= 0 || errorRate >= 1 {
+			return
+		}
+
+		ticker := fuzzTicker(purchase, previous, current, prices)
+		prediction, err := PredictWithOptions(
+			ticker, PredictOptions{ErrorRate: errorRate},
+		)
+		if xerrors.Is(err, errs.ErrInvalidTicker) {
+			return
+		}
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assertPredictionInvariants(t, ticker, prediction, true)
+	})
+}
@@ -0,0 +1,232 @@
+// Package gamesim is a stand-alone reimplementation of the routine Animal Crossing:
+// New Horizons uses to generate a week of turnip prices, as decompiled by Ninji:
+// https://gist.github.com/Treeki/85be14d297c80c8b3c0a76375743325b
+//
+// It deliberately shares no code with the models package so that it can be used to
+// check the predictor's work.
+package gamesim
+
+// Pattern is the game's index for a week's price pattern.
+type Pattern int
+
+const (
+	Fluctuating Pattern = 0
+	BigSpike    Pattern = 1
+	Decreasing  Pattern = 2
+	SmallSpike  Pattern = 3
+	// Used as the previous pattern when it is not known, the same as the game does
+	// for an island's first week.
+	Unknown Pattern = 4
+)
+
+// The number of price periods in a week, monday morning through saturday afternoon.
+const PricePeriodCount = 12
+
+// Week is a single week of prices generated by the game's routine.
+type Week struct {
+	PreviousPattern Pattern
+	Pattern         Pattern
+	PurchasePrice   int
+	Prices          [PricePeriodCount]int
+}
+
+// Chance out of 100 of moving from each previous pattern to each next pattern. An
+// unknown previous pattern always moves to decreasing, but players who have not
+// sold turnips before are not covered here.
+var transitions = [4][4]int{
+	Fluctuating: {20, 30, 15, 35},
+	BigSpike:    {50, 5, 20, 25},
+	Decreasing:  {25, 45, 5, 25},
+	SmallSpike:  {45, 25, 15, 15},
+}
+
+// Simulate generates a week of prices from the previous week's pattern.
+func Simulate(random *Random, previousPattern Pattern) *Week {
+	week := &Week{
+		PreviousPattern: previousPattern,
+		PurchasePrice:   random.Int(90, 110),
+	}
+
+	// The game rolls for the next pattern even if the previous one is unknown.
+	chance := random.Int(0, 99)
+	week.Pattern = nextPattern(previousPattern, chance)
+
+	// The game writes into a 14-price array where the first two slots are sunday.
+	// We'll keep that layout while generating so the indexes match the decompiled
+	// code, then copy the weekday prices out.
+	sellPrices := [PricePeriodCount + 2]int{}
+	basePrice := week.PurchasePrice
+
+	switch week.Pattern {
+	case Fluctuating:
+		simulateFluctuating(random, basePrice, &sellPrices)
+	case BigSpike:
+		simulateBigSpike(random, basePrice, &sellPrices)
+	case Decreasing:
+		simulateDecreasing(random, basePrice, &sellPrices)
+	case SmallSpike:
+		simulateSmallSpike(random, basePrice, &sellPrices)
+	}
+
+	copy(week.Prices[:], sellPrices[2:])
+	return week
+}
+
+func nextPattern(previousPattern Pattern, chance int) Pattern {
+	if previousPattern < Fluctuating || previousPattern >= Unknown {
+		return Decreasing
+	}
+
+	for pattern, patternChance := range transitions[previousPattern] {
+		if chance < patternChance {
+			return Pattern(pattern)
+		}
+		chance -= patternChance
+	}
+
+	// Unreachable: each row adds up to 100.
+	return SmallSpike
+}
+
+// Returns a price from basePrice and a multiplier the game rolled from a to b.
+func randomPrice(random *Random, basePrice int, a float32, b float32) int {
+	return intCeil(random.Float(a, b) * float32(basePrice))
+}
+
+// Fills in count prices starting at work with a rate that starts between rateA and
+// rateB, then drops by decrease plus up to decreaseRandom each period. Returns the
+// next work index.
+func fillDecreasing(
+	random *Random,
+	basePrice int,
+	sellPrices *[PricePeriodCount + 2]int,
+	work int,
+	count int,
+	rate float32,
+	decrease float32,
+	decreaseRandom float32,
+) int {
+	for i := 0; i < count; i++ {
+		sellPrices[work] = intCeil(rate * float32(basePrice))
+		work++
+		rate -= decrease
+		rate -= random.Float(0, decreaseRandom)
+	}
+	return work
+}
+
+// PATTERN 0: high, decreasing, high, decreasing, high
+func simulateFluctuating(
+	random *Random, basePrice int, sellPrices *[PricePeriodCount + 2]int,
+) {
+	work := 2
+
+	decPhaseLen1 := 2
+	if random.Bool() {
+		decPhaseLen1 = 3
+	}
+	decPhaseLen2 := 5 - decPhaseLen1
+
+	hiPhaseLen1 := random.Int(0, 6)
+	hiPhaseLen2and3 := 7 - hiPhaseLen1
+	hiPhaseLen3 := random.Int(0, hiPhaseLen2and3-1)
+
+	for i := 0; i < hiPhaseLen1; i++ {
+		sellPrices[work] = randomPrice(random, basePrice, 0.9, 1.4)
+		work++
+	}
+
+	rate := random.Float(0.8, 0.6)
+	work = fillDecreasing(
+		random, basePrice, sellPrices, work, decPhaseLen1, rate, 0.04, 0.06,
+	)
+
+	for i := 0; i < hiPhaseLen2and3-hiPhaseLen3; i++ {
+		sellPrices[work] = randomPrice(random, basePrice, 0.9, 1.4)
+		work++
+	}
+
+	rate = random.Float(0.8, 0.6)
+	work = fillDecreasing(
+		random, basePrice, sellPrices, work, decPhaseLen2, rate, 0.04, 0.06,
+	)
+
+	for i := 0; i < hiPhaseLen3; i++ {
+		sellPrices[work] = randomPrice(random, basePrice, 0.9, 1.4)
+		work++
+	}
+}
+
+// PATTERN 1: decreasing middle, high spike, random low
+func simulateBigSpike(
+	random *Random, basePrice int, sellPrices *[PricePeriodCount + 2]int,
+) {
+	peakStart := random.Int(3, 9)
+
+	rate := random.Float(0.9, 0.85)
+	work := fillDecreasing(
+		random, basePrice, sellPrices, 2, peakStart-2, rate, 0.03, 0.02,
+	)
+
+	spikeRates := [][2]float32{{0.9, 1.4}, {1.4, 2.0}, {2.0, 6.0}, {1.4, 2.0}, {0.9, 1.4}}
+	for _, spikeRate := range spikeRates {
+		sellPrices[work] = randomPrice(random, basePrice, spikeRate[0], spikeRate[1])
+		work++
+	}
+
+	for ; work < len(sellPrices); work++ {
+		sellPrices[work] = randomPrice(random, basePrice, 0.4, 0.9)
+	}
+}
+
+// PATTERN 2: consistently decreasing
+func simulateDecreasing(
+	random *Random, basePrice int, sellPrices *[PricePeriodCount + 2]int,
+) {
+	var rate float32 = 0.9
+	rate -= random.Float(0, 0.05)
+	fillDecreasing(
+		random, basePrice, sellPrices, 2, PricePeriodCount, rate, 0.03, 0.02,
+	)
+}
+
+// PATTERN 3: decreasing, spike, decreasing
+func simulateSmallSpike(
+	random *Random, basePrice int, sellPrices *[PricePeriodCount + 2]int,
+) {
+	peakStart := random.Int(2, 9)
+
+	rate := random.Float(0.9, 0.4)
+	work := fillDecreasing(
+		random, basePrice, sellPrices, 2, peakStart-2, rate, 0.03, 0.02,
+	)
+
+	sellPrices[work] = randomPrice(random, basePrice, 0.9, 1.4)
+	work++
+	sellPrices[work] = randomPrice(random, basePrice, 0.9, 1.4)
+	work++
+
+	// The peak is rolled first, and the prices on either side of it are rolled up
+	// to the peak rate, then knocked down by a bell.
+	rate = random.Float(1.4, 2.0)
+	sellPrices[work] = randomPrice(random, basePrice, 1.4, rate) - 1
+	work++
+	sellPrices[work] = intCeil(rate * float32(basePrice))
+	work++
+	sellPrices[work] = randomPrice(random, basePrice, 1.4, rate) - 1
+	work++
+
+	if work < len(sellPrices) {
+		rate = random.Float(0.9, 0.4)
+		fillDecreasing(
+			random,
+			basePrice,
+			sellPrices,
+			work,
+			len(sellPrices)-work,
+			rate,
+			0.03,
+			0.02,
+		)
+	}
+}
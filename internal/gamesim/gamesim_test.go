@@ -0,0 +1,92 @@
+package gamesim
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRandomSeedRepeats(t *testing.T) {
+	assert := assert.New(t)
+
+	first := new(Random)
+	first.Seed(1234)
+	second := new(Random)
+	second.Seed(1234)
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(first.Uint32(), second.Uint32())
+	}
+}
+
+func TestRandomRanges(t *testing.T) {
+	assert := assert.New(t)
+
+	random := new(Random)
+	random.Seed(1)
+
+	for i := 0; i < 10000; i++ {
+		value := random.Int(90, 110)
+		assert.GreaterOrEqual(value, 90)
+		assert.LessOrEqual(value, 110)
+
+		// The game often passes the larger bound first.
+		float := random.Float(0.9, 0.4)
+		assert.GreaterOrEqual(float, float32(0.4))
+		assert.LessOrEqual(float, float32(0.9))
+	}
+}
+
+func TestIntCeil(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(90, intCeil(90))
+	assert.Equal(91, intCeil(90.1))
+	// Just over a whole number rounds down, unlike math.Ceil.
+	assert.Equal(1, intCeil(1.000001))
+}
+
+func TestSimulatePatternChances(t *testing.T) {
+	const weekCount = 20000
+
+	random := new(Random)
+	random.Seed(1)
+
+	for previous := Fluctuating; previous <= Unknown; previous++ {
+		var counts [4]int
+		for i := 0; i < weekCount; i++ {
+			week := Simulate(random, previous)
+			counts[week.Pattern]++
+
+			assert.GreaterOrEqual(t, week.PurchasePrice, 90)
+			assert.LessOrEqual(t, week.PurchasePrice, 110)
+			for _, price := range week.Prices {
+				assert.Greater(t, price, 0)
+				assert.LessOrEqual(t, price, 660)
+			}
+		}
+
+		for pattern, count := range counts {
+			expected := 0.0
+			if previous == Unknown {
+				if Pattern(pattern) == Decreasing {
+					expected = 1
+				}
+			} else {
+				expected = float64(transitions[previous][pattern]) / 100
+			}
+
+			assert.InDelta(
+				t,
+				expected,
+				float64(count)/weekCount,
+				0.015,
+				"previous %v, pattern %v",
+				previous,
+				pattern,
+			)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package gamesim
+
+import (
+	"math"
+)
+
+// Random is a port of the sead::Random xorshift generator the game uses to pick
+// turnip prices. The same seed will always produce the same sequence.
+type Random struct {
+	context [4]uint32
+}
+
+// Seeds the generator the same way sead::Random::init(u32) does.
+func (random *Random) Seed(seed uint32) {
+	const multiplier = 0x6C078965
+
+	previous := seed
+	for i := range random.context {
+		previous = multiplier*(previous^(previous>>30)) + uint32(i+1)
+		random.context[i] = previous
+	}
+}
+
+// Returns the next raw 32-bit value.
+func (random *Random) Uint32() uint32 {
+	context := &random.context
+
+	n := context[0] ^ (context[0] << 11)
+	context[0] = context[1]
+	context[1] = context[2]
+	context[2] = context[3]
+	context[3] = n ^ (n >> 8) ^ context[3] ^ (context[3] >> 19)
+
+	return context[3]
+}
+
+// Returns true or false with equal odds by checking the top bit.
+func (random *Random) Bool() bool {
+	return random.Uint32()&0x80000000 != 0
+}
+
+// Returns an int from min to max inclusive.
+func (random *Random) Int(min int, max int) int {
+	span := uint64(max - min + 1)
+	return int((uint64(random.Uint32())*span)>>32) + min
+}
+
+// Returns a float between a and b. The game builds a float in [1, 2) by stuffing 23
+// random bits into the mantissa, then scales it to the range. Note that a does not
+// need to be less than b, and the game often calls it with the larger value first.
+func (random *Random) Float(a float32, b float32) float32 {
+	bits := uint32(0x3F800000) | (random.Uint32() >> 9)
+	unit := math.Float32frombits(bits) - 1
+	return a + unit*(b-a)
+}
+
+// Rounds a price up the way the game does: by adding just under 1 and truncating.
+// This is NOT the same as math.Ceil for values a hair over a whole number.
+func intCeil(value float32) int {
+	return int(value + 0.99999)
+}
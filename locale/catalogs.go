@@ -0,0 +1,322 @@
+package locale
+
+import (
+	"github.com/peake100/turnup-go/models"
+)
+
+var englishMessages = &messages{
+	language: "en",
+	patterns: map[models.PricePattern]string{
+		models.FLUCTUATING: "Fluctuating",
+		models.BIGSPIKE:    "Big Spike",
+		models.DECREASING:  "Decreasing",
+		models.SMALLSPIKE:  "Small Spike",
+		models.UNKNOWN:     "Unknown",
+	},
+	patternAliases: map[string]models.PricePattern{
+		"Random":       models.FLUCTUATING,
+		"Big Spikes":   models.BIGSPIKE,
+		"Large Spike":  models.BIGSPIKE,
+		"Large Spikes": models.BIGSPIKE,
+		"Small Spikes": models.SMALLSPIKE,
+	},
+	// The decreasing phase reports itself as "whomp whomp", which was never meant to be
+	// shown to anyone.
+	phases: map[string]string{
+		models.PhaseSteadyDecrease:   "steady decrease",
+		models.PhaseSharpIncrease:    "sharp increase",
+		models.PhaseSharpDecrease:    "sharp decrease",
+		models.PhaseRandomLow:        "random low",
+		models.PhaseConstantDecrease: "constant decrease",
+		models.PhaseMildIncrease:     "mild increase",
+		models.PhaseMildDecrease:     "mild decrease",
+		models.PhaseSmallSpike:       "small spike",
+	},
+	weekdays: [7]string{
+		"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+	},
+	timesOfDay:   [2]string{"AM", "PM"},
+	periodFormat: "%[1]v %[2]v",
+}
+
+var japaneseMessages = &messages{
+	language: "ja",
+	patterns: map[models.PricePattern]string{
+		models.FLUCTUATING: "波型",
+		models.BIGSPIKE:    "跳ね大型",
+		models.DECREASING:  "減少型",
+		models.SMALLSPIKE:  "跳ね小型",
+		models.UNKNOWN:     "不明",
+	},
+	patternAliases: map[string]models.PricePattern{
+		"ジグザグ型": models.FLUCTUATING,
+		"3期型":   models.BIGSPIKE,
+		"跳ね大":   models.BIGSPIKE,
+		"4期型":   models.SMALLSPIKE,
+		"跳ね小":   models.SMALLSPIKE,
+		"わからない": models.UNKNOWN,
+	},
+	phases: map[string]string{
+		models.PhaseSteadyDecrease:   "緩やかな下落",
+		models.PhaseSharpIncrease:    "急上昇",
+		models.PhaseSharpDecrease:    "急下落",
+		models.PhaseRandomLow:        "低迷",
+		models.PhaseConstantDecrease: "下落続き",
+		models.PhaseMildIncrease:     "小幅な上昇",
+		models.PhaseMildDecrease:     "小幅な下落",
+		models.PhaseSmallSpike:       "小さな跳ね",
+	},
+	weekdays: [7]string{
+		"日曜日", "月曜日", "火曜日", "水曜日",
+		"木曜日", "金曜日", "土曜日",
+	},
+	timesOfDay:   [2]string{"午前", "午後"},
+	periodFormat: "%[1]v%[2]v",
+	explain: map[models.ExplainMessage]string{
+		models.ExplainChance:         "%.0f%%",
+		models.ExplainChanceUnderOne: "1%未満",
+
+		models.ExplainPatternPrice:           "%[1]vは%[2]v：%[3]v",
+		models.ExplainPatternPreviousPattern: "%[1]vは%[2]v：先週のパターンの後には来ない",
+		models.ExplainPatternConstraints:     "%[1]vは%[2]v：予測の条件で除外された",
+		models.ExplainPatternPrior:           "%[1]vは%[2]v：先週のパターンによる",
+
+		models.ExplainWeek:          "%[1]v：%[2]v",
+		models.ExplainWeekPrice:     "%[1]v：%[2]v。%[3]v",
+		models.ExplainListSeparator: "、",
+		models.ExplainSpan:          "%[1]v（%[2]v）",
+		models.ExplainSpanRange:     "%[1]v（%[2]v〜%[3]v）",
+
+		models.ExplainStayed:  "%[1]vは%[2]vベルのままだった",
+		models.ExplainRose:    "%[1]vは%[3]vから%.0[2]f%%上がった",
+		models.ExplainFell:    "%[1]vは%[3]vから%.0[2]f%%下がった",
+		models.ExplainWas:     "%[1]vは%[2]vベルだった",
+		models.ExplainAtLeast: "%[1]vは%[2]vベル以上だった",
+		models.ExplainAtMost:  "%[1]vは%[2]vベル以下だった",
+		models.ExplainBetween: "%[1]vは%[2]v〜%[3]vベルだった",
+
+		models.ExplainPhase:   "%vの段階",
+		models.ExplainPhases:  "%vの段階",
+		models.ExplainPhaseOr: "%[1]vか%[2]v",
+
+		models.ExplainCantMake:           "%[1]vが、%[2]vではありえない",
+		models.ExplainCantMakeRange:      "%[1]vが、%[2]vではありえない（%[3]v〜%[4]vベル）",
+		models.ExplainFits:               "%[1]vので、%[2]vに合う",
+		models.ExplainLessLikelyWeeks:    "%[1]vが、%[2]vでは他の週よりありえにくい",
+		models.ExplainLessLikelyPatterns: "%[1]vが、%[2]vでは他のパターンよりありえにくい",
+	},
+}
+
+var frenchMessages = &messages{
+	language: "fr",
+	patterns: map[models.PricePattern]string{
+		models.FLUCTUATING: "Fluctuant",
+		models.BIGSPIKE:    "Grand pic",
+		models.DECREASING:  "Décroissant",
+		models.SMALLSPIKE:  "Petit pic",
+		models.UNKNOWN:     "Inconnu",
+	},
+	patternAliases: map[string]models.PricePattern{
+		"Aléatoire": models.FLUCTUATING,
+		"Gros pic":  models.BIGSPIKE,
+		"En baisse": models.DECREASING,
+	},
+	phases: map[string]string{
+		models.PhaseSteadyDecrease:   "baisse régulière",
+		models.PhaseSharpIncrease:    "forte hausse",
+		models.PhaseSharpDecrease:    "forte baisse",
+		models.PhaseRandomLow:        "prix bas aléatoires",
+		models.PhaseConstantDecrease: "baisse continue",
+		models.PhaseMildIncrease:     "légère hausse",
+		models.PhaseMildDecrease:     "légère baisse",
+		models.PhaseSmallSpike:       "petit pic",
+	},
+	weekdays: [7]string{
+		"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi",
+	},
+	timesOfDay:   [2]string{"matin", "après-midi"},
+	periodFormat: "%[1]v %[2]v",
+	explain: map[models.ExplainMessage]string{
+		models.ExplainChance:         "%.0f %%",
+		models.ExplainChanceUnderOne: "moins de 1 %",
+
+		models.ExplainPatternPrice: "%[1]v est à %[2]v car %[3]v",
+		models.ExplainPatternPreviousPattern: "%[1]v est à %[2]v car il ne peut pas " +
+			"suivre le profil de la semaine dernière",
+		models.ExplainPatternConstraints: "%[1]v est à %[2]v car les contraintes de " +
+			"la prédiction l'excluent",
+		models.ExplainPatternPrior: "%[1]v est à %[2]v d'après le profil de la " +
+			"semaine dernière",
+
+		models.ExplainWeek:          "%[1]v : %[2]v",
+		models.ExplainWeekPrice:     "%[1]v : %[2]v, car %[3]v",
+		models.ExplainListSeparator: ", ",
+		models.ExplainSpan:          "%[1]v (%[2]v)",
+		models.ExplainSpanRange:     "%[1]v (de %[2]v à %[3]v)",
+
+		models.ExplainStayed:  "%[1]v est resté à %[2]v clochettes",
+		models.ExplainRose:    "%[1]v a augmenté de %.0[2]f %% par rapport à %[3]v",
+		models.ExplainFell:    "%[1]v a baissé de %.0[2]f %% par rapport à %[3]v",
+		models.ExplainWas:     "%[1]v était à %[2]v clochettes",
+		models.ExplainAtLeast: "%[1]v était à au moins %[2]v clochettes",
+		models.ExplainAtMost:  "%[1]v était à au plus %[2]v clochettes",
+		models.ExplainBetween: "%[1]v était entre %[2]v et %[3]v clochettes",
+
+		models.ExplainPhase:   "la phase de %v",
+		models.ExplainPhases:  "les phases de %v",
+		models.ExplainPhaseOr: "%[1]v ou %[2]v",
+
+		models.ExplainCantMake: "%[1]v, ce qui est impossible pour %[2]v",
+		models.ExplainCantMakeRange: "%[1]v, ce qui est impossible pour %[2]v " +
+			"(%[3]v-%[4]v clochettes)",
+		models.ExplainFits: "%[1]v, ce qui correspond à %[2]v",
+		models.ExplainLessLikelyWeeks: "%[1]v, ce qui est moins probable dans %[2]v " +
+			"que dans les autres semaines",
+		models.ExplainLessLikelyPatterns: "%[1]v, ce qui est moins probable dans " +
+			"%[2]v que dans les autres profils",
+	},
+}
+
+var germanMessages = &messages{
+	language: "de",
+	patterns: map[models.PricePattern]string{
+		models.FLUCTUATING: "Schwankend",
+		models.BIGSPIKE:    "Große Spitze",
+		models.DECREASING:  "Fallend",
+		models.SMALLSPIKE:  "Kleine Spitze",
+		models.UNKNOWN:     "Unbekannt",
+	},
+	patternAliases: map[string]models.PricePattern{
+		"Zufällig":    models.FLUCTUATING,
+		"Hohe Spitze": models.BIGSPIKE,
+		"Abnehmend":   models.DECREASING,
+	},
+	phases: map[string]string{
+		models.PhaseSteadyDecrease:   "stetiger Rückgang",
+		models.PhaseSharpIncrease:    "starker Anstieg",
+		models.PhaseSharpDecrease:    "starker Rückgang",
+		models.PhaseRandomLow:        "zufälliges Tief",
+		models.PhaseConstantDecrease: "anhaltender Rückgang",
+		models.PhaseMildIncrease:     "leichter Anstieg",
+		models.PhaseMildDecrease:     "leichter Rückgang",
+		models.PhaseSmallSpike:       "kleine Spitze",
+	},
+	weekdays: [7]string{
+		"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag",
+	},
+	timesOfDay:   [2]string{"Vormittag", "Nachmittag"},
+	periodFormat: "%[1]v %[2]v",
+	explain: map[models.ExplainMessage]string{
+		models.ExplainChance:         "%.0f %%",
+		models.ExplainChanceUnderOne: "unter 1 %",
+
+		models.ExplainPatternPrice: "%[1]v liegt bei %[2]v, denn %[3]v",
+		models.ExplainPatternPreviousPattern: "%[1]v liegt bei %[2]v, denn es kann " +
+			"nicht auf das Muster der letzten Woche folgen",
+		models.ExplainPatternConstraints: "%[1]v liegt bei %[2]v, denn die Vorgaben " +
+			"der Vorhersage schließen es aus",
+		models.ExplainPatternPrior: "%[1]v liegt bei %[2]v, ausgehend vom Muster der " +
+			"letzten Woche",
+
+		models.ExplainWeek:          "%[1]v: %[2]v",
+		models.ExplainWeekPrice:     "%[1]v: %[2]v, denn %[3]v",
+		models.ExplainListSeparator: ", ",
+		models.ExplainSpan:          "%[1]v (%[2]v)",
+		models.ExplainSpanRange:     "%[1]v (%[2]v bis %[3]v)",
+
+		models.ExplainStayed:  "%[1]v blieb bei %[2]v Sternis",
+		models.ExplainRose:    "%[1]v stieg um %.0[2]f %% gegenüber %[3]v",
+		models.ExplainFell:    "%[1]v fiel um %.0[2]f %% gegenüber %[3]v",
+		models.ExplainWas:     "%[1]v lag bei %[2]v Sternis",
+		models.ExplainAtLeast: "%[1]v lag bei mindestens %[2]v Sternis",
+		models.ExplainAtMost:  "%[1]v lag bei höchstens %[2]v Sternis",
+		models.ExplainBetween: "%[1]v lag zwischen %[2]v und %[3]v Sternis",
+
+		// Phase names are quoted, so the names joined by ExplainPhaseOr close and open
+		// the quotes between them.
+		models.ExplainPhase:   "die Phase „%v“",
+		models.ExplainPhases:  "die Phasen „%v“",
+		models.ExplainPhaseOr: "%[1]v“ oder „%[2]v",
+
+		models.ExplainCantMake: "%[1]v, was für %[2]v unmöglich ist",
+		models.ExplainCantMakeRange: "%[1]v, was für %[2]v unmöglich ist " +
+			"(%[3]v-%[4]v Sternis)",
+		models.ExplainFits: "%[1]v, was für %[2]v typisch ist",
+		models.ExplainLessLikelyWeeks: "%[1]v, was für %[2]v unwahrscheinlicher ist " +
+			"als für andere Wochen",
+		models.ExplainLessLikelyPatterns: "%[1]v, was für %[2]v unwahrscheinlicher " +
+			"ist als für andere Muster",
+	},
+}
+
+var spanishMessages = &messages{
+	language: "es",
+	patterns: map[models.PricePattern]string{
+		models.FLUCTUATING: "Fluctuante",
+		models.BIGSPIKE:    "Pico grande",
+		models.DECREASING:  "Decreciente",
+		models.SMALLSPIKE:  "Pico pequeño",
+		models.UNKNOWN:     "Desconocido",
+	},
+	patternAliases: map[string]models.PricePattern{
+		"Aleatorio":   models.FLUCTUATING,
+		"Gran pico":   models.BIGSPIKE,
+		"Pico alto":   models.BIGSPIKE,
+		"Pico bajo":   models.SMALLSPIKE,
+		"Decreciendo": models.DECREASING,
+	},
+	phases: map[string]string{
+		models.PhaseSteadyDecrease:   "bajada gradual",
+		models.PhaseSharpIncrease:    "subida brusca",
+		models.PhaseSharpDecrease:    "bajada brusca",
+		models.PhaseRandomLow:        "precios bajos aleatorios",
+		models.PhaseConstantDecrease: "bajada continua",
+		models.PhaseMildIncrease:     "subida leve",
+		models.PhaseMildDecrease:     "bajada leve",
+		models.PhaseSmallSpike:       "pico pequeño",
+	},
+	weekdays: [7]string{
+		"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado",
+	},
+	timesOfDay:   [2]string{"mañana", "tarde"},
+	periodFormat: "%[1]v por la %[2]v",
+	explain: map[models.ExplainMessage]string{
+		models.ExplainChance:         "%.0f %%",
+		models.ExplainChanceUnderOne: "menos del 1 %",
+
+		models.ExplainPatternPrice: "%[1]v tiene un %[2]v porque %[3]v",
+		models.ExplainPatternPreviousPattern: "%[1]v tiene un %[2]v porque no puede " +
+			"seguir al patrón de la semana pasada",
+		models.ExplainPatternConstraints: "%[1]v tiene un %[2]v porque las " +
+			"restricciones de la predicción lo descartan",
+		models.ExplainPatternPrior: "%[1]v tiene un %[2]v según el patrón de la " +
+			"semana pasada",
+
+		models.ExplainWeek:          "%[1]v: %[2]v",
+		models.ExplainWeekPrice:     "%[1]v: %[2]v, porque %[3]v",
+		models.ExplainListSeparator: ", ",
+		models.ExplainSpan:          "%[1]v (%[2]v)",
+		models.ExplainSpanRange:     "%[1]v (de %[2]v a %[3]v)",
+
+		models.ExplainStayed:  "el %[1]v se mantuvo en %[2]v bayas",
+		models.ExplainRose:    "el %[1]v subió un %.0[2]f %% respecto al %[3]v",
+		models.ExplainFell:    "el %[1]v bajó un %.0[2]f %% respecto al %[3]v",
+		models.ExplainWas:     "el %[1]v estaba a %[2]v bayas",
+		models.ExplainAtLeast: "el %[1]v estaba a %[2]v bayas como mínimo",
+		models.ExplainAtMost:  "el %[1]v estaba a %[2]v bayas como máximo",
+		models.ExplainBetween: "el %[1]v estaba entre %[2]v y %[3]v bayas",
+
+		models.ExplainPhase:   "la fase de %v",
+		models.ExplainPhases:  "las fases de %v",
+		models.ExplainPhaseOr: "%[1]v o %[2]v",
+
+		models.ExplainCantMake: "%[1]v, algo imposible para %[2]v",
+		models.ExplainCantMakeRange: "%[1]v, algo imposible para %[2]v " +
+			"(%[3]v-%[4]v bayas)",
+		models.ExplainFits: "%[1]v, lo que encaja con %[2]v",
+		models.ExplainLessLikelyWeeks: "%[1]v, lo que es menos probable en %[2]v " +
+			"que en otras semanas",
+		models.ExplainLessLikelyPatterns: "%[1]v, lo que es menos probable en %[2]v " +
+			"que en otros patrones",
+	},
+}
@@ -0,0 +1,295 @@
+// Package locale formats and parses the names of patterns, phases, weekdays and times
+// of day in the languages our community speaks.
+//
+// Each language has a Catalog. Catalogs are looked up by language tag, and tags with a
+// region like "en-US" or "pt_BR" use the catalog for their language:
+//
+//	catalog, err := locale.Lookup("fr-CA")
+//	fmt.Println(catalog.Pattern(models.BIGSPIKE)) // Grand pic
+//
+// Parsing ignores case, spaces, dashes and accents, so "grand pic", "GRANDPIC" and
+// "Grand-Pic" are all read as a big spike. Every catalog also reads the English names.
+//
+// Catalogs also write explanations of predictions:
+//
+//	explanation := prediction.ExplainIn(locale.Japanese)
+package locale
+
+import (
+	"fmt"
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/models/timeofday"
+	"github.com/peake100/turnup-go/values"
+	"strings"
+	"time"
+)
+
+// The names for a single language, which catalogs are built from.
+type messages struct {
+	// The language tag, like "en".
+	language string
+	// The name of each pattern.
+	patterns map[models.PricePattern]string
+	// Other names each pattern goes by, which are parsed but never formatted.
+	patternAliases map[string]models.PricePattern
+	// The name of each phase, keyed by the name the phase reports from
+	// PatternPhase.Name().
+	phases map[string]string
+	// The name of each weekday, indexed by time.Weekday.
+	weekdays [7]string
+	// The name of each time of day, indexed by ToD.PhaseOffset().
+	timesOfDay [2]string
+	// Formats a price period. The weekday name is the first argument and the time of
+	// day name the second.
+	periodFormat string
+	// The format string of each explanation message. Messages that are missing use
+	// the English ones from models.
+	explain map[models.ExplainMessage]string
+}
+
+// Catalog formats and parses names in a single language.
+type Catalog struct {
+	messages *messages
+
+	// Normalized names to the value they parse to.
+	patternValues map[string]models.PricePattern
+	phaseValues   map[string]string
+	weekdayValues map[string]time.Weekday
+	todValues     map[string]timeofday.ToD
+	periodValues  map[string]models.PricePeriod
+}
+
+// The catalogs for each supported language.
+var (
+	English  = newCatalog(englishMessages)
+	Japanese = newCatalog(japaneseMessages)
+	French   = newCatalog(frenchMessages)
+	German   = newCatalog(germanMessages)
+	Spanish  = newCatalog(spanishMessages)
+)
+
+// Catalogs can be passed to Prediction.ExplainIn.
+var _ models.ExplainLanguage = English
+
+// Every catalog, in the order they are tried by the package-level parse functions.
+var catalogs = []*Catalog{English, Japanese, French, German, Spanish}
+
+func newCatalog(language *messages) *Catalog {
+	catalog := &Catalog{
+		messages:      language,
+		patternValues: make(map[string]models.PricePattern),
+		phaseValues:   make(map[string]string),
+		weekdayValues: make(map[string]time.Weekday),
+		todValues:     make(map[string]timeofday.ToD),
+		periodValues:  make(map[string]models.PricePeriod),
+	}
+
+	// English is added first so this language's names win if the two clash.
+	languages := []*messages{englishMessages}
+	if language != englishMessages {
+		languages = append(languages, language)
+	}
+
+	for _, names := range languages {
+		for pattern, name := range names.patterns {
+			catalog.patternValues[normalize(name)] = pattern
+		}
+		for name, pattern := range names.patternAliases {
+			catalog.patternValues[normalize(name)] = pattern
+		}
+		for phase, name := range names.phases {
+			catalog.phaseValues[normalize(name)] = phase
+			catalog.phaseValues[normalize(phase)] = phase
+		}
+		for weekday, name := range names.weekdays {
+			catalog.weekdayValues[normalize(name)] = time.Weekday(weekday)
+		}
+		for _, tod := range []timeofday.ToD{timeofday.AM, timeofday.PM} {
+			catalog.todValues[normalize(names.timesOfDay[tod.PhaseOffset()])] = tod
+		}
+		for period := 0; period < values.PricePeriodCount; period++ {
+			name := normalize(names.period(models.PricePeriod(period)))
+			catalog.periodValues[name] = models.PricePeriod(period)
+		}
+	}
+
+	return catalog
+}
+
+// Lookup returns the catalog for a language tag like "ja" or "es-MX". Only the
+// language part of the tag is used. Returns errs.ErrUnknownLanguage if we don't have
+// a catalog for the language.
+func Lookup(tag string) (*Catalog, error) {
+	language := strings.ToLower(tag)
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+
+	for _, catalog := range catalogs {
+		if catalog.messages.language == language {
+			return catalog, nil
+		}
+	}
+	return nil, errs.ErrUnknownLanguage
+}
+
+// Languages returns the tags of every language with a catalog.
+func Languages() []string {
+	languages := make([]string, len(catalogs))
+	for i, catalog := range catalogs {
+		languages[i] = catalog.messages.language
+	}
+	return languages
+}
+
+// The language tag of the catalog, like "en".
+func (catalog *Catalog) Language() string {
+	return catalog.messages.language
+}
+
+// Pattern returns the name of a pattern. Patterns the catalog has no name for, like
+// custom patterns, use PricePattern.String().
+func (catalog *Catalog) Pattern(pattern models.PricePattern) string {
+	if name, ok := catalog.messages.patterns[pattern]; ok {
+		return name
+	}
+	return pattern.String()
+}
+
+// Phase returns the name of a phase from the name it reports from PatternPhase.Name().
+// Phases the catalog has no name for, like those of custom patterns, are returned as
+// is.
+func (catalog *Catalog) Phase(name string) string {
+	if translated, ok := catalog.messages.phases[name]; ok {
+		return translated
+	}
+	return name
+}
+
+// Weekday returns the name of a weekday.
+func (catalog *Catalog) Weekday(weekday time.Weekday) string {
+	if weekday < time.Sunday || weekday > time.Saturday {
+		return weekday.String()
+	}
+	return catalog.messages.weekdays[weekday]
+}
+
+// ToD returns the name of a time of day.
+func (catalog *Catalog) ToD(tod timeofday.ToD) string {
+	if tod != timeofday.AM && tod != timeofday.PM {
+		return string(tod)
+	}
+	return catalog.messages.timesOfDay[tod.PhaseOffset()]
+}
+
+// Period returns the name of a price period, like "Tuesday PM".
+func (catalog *Catalog) Period(period models.PricePeriod) string {
+	if period < 0 || period >= values.PricePeriodCount {
+		return fmt.Sprintf("PricePeriod(%d)", int(period))
+	}
+	return catalog.messages.period(period)
+}
+
+// Message formats a message of an explanation with its arguments. Catalogs are
+// passed to Prediction.ExplainIn to explain a prediction in their language.
+func (catalog *Catalog) Message(
+	message models.ExplainMessage, args ...interface{},
+) string {
+	format, ok := catalog.messages.explain[message]
+	if !ok {
+		format = message.English()
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (messages *messages) period(period models.PricePeriod) string {
+	return fmt.Sprintf(
+		messages.periodFormat,
+		messages.weekdays[period.Weekday()],
+		messages.timesOfDay[period.ToD().PhaseOffset()],
+	)
+}
+
+// ParsePattern returns the pattern with a name or alias of ``value`` in this language
+// or English. Returns errs.ErrPatternStringValue if there is none.
+func (catalog *Catalog) ParsePattern(value string) (models.PricePattern, error) {
+	if pattern, ok := catalog.patternValues[normalize(value)]; ok {
+		return pattern, nil
+	}
+	return models.UNKNOWN, errs.ErrPatternStringValue
+}
+
+// ParsePhase returns the name a phase reports from PatternPhase.Name() for a phase
+// name in this language or English. Returns errs.ErrPhaseStringValue if there is
+// none.
+func (catalog *Catalog) ParsePhase(value string) (string, error) {
+	if phase, ok := catalog.phaseValues[normalize(value)]; ok {
+		return phase, nil
+	}
+	return "", errs.ErrPhaseStringValue
+}
+
+// ParseWeekday returns the weekday named ``value`` in this language or English.
+// Returns errs.ErrWeekdayStringValue if there is none.
+func (catalog *Catalog) ParseWeekday(value string) (time.Weekday, error) {
+	if weekday, ok := catalog.weekdayValues[normalize(value)]; ok {
+		return weekday, nil
+	}
+	return time.Sunday, errs.ErrWeekdayStringValue
+}
+
+// ParseToD returns the time of day named ``value`` in this language or English.
+// Returns errs.ErrTimeOfDayStringValue if there is none.
+func (catalog *Catalog) ParseToD(value string) (timeofday.ToD, error) {
+	if tod, ok := catalog.todValues[normalize(value)]; ok {
+		return tod, nil
+	}
+	return "", errs.ErrTimeOfDayStringValue
+}
+
+// ParsePeriod returns the price period named ``value`` in this language or English,
+// as written by Period. Returns errs.ErrPricePeriodStringValue if there is none.
+func (catalog *Catalog) ParsePeriod(value string) (models.PricePeriod, error) {
+	if period, ok := catalog.periodValues[normalize(value)]; ok {
+		return period, nil
+	}
+	return 0, errs.ErrPricePeriodStringValue
+}
+
+// ParsePattern returns the pattern with a name or alias of ``value`` in any of our
+// languages. Useful when we don't know what language a user is writing in. Returns
+// errs.ErrPatternStringValue if no catalog knows the name.
+func ParsePattern(value string) (models.PricePattern, error) {
+	for _, catalog := range catalogs {
+		if pattern, err := catalog.ParsePattern(value); err == nil {
+			return pattern, nil
+		}
+	}
+	return models.UNKNOWN, errs.ErrPatternStringValue
+}
+
+// Removes accents from the latin letters our languages use.
+var accentFolder = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ä", "a",
+	"ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"í", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ó", "o", "ô", "o", "ö", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ß", "ss",
+)
+
+// Lower-cases ``value`` and removes accents, spaces, dashes and underscores so names
+// can be compared the way people type them.
+func normalize(value string) string {
+	value = accentFolder.Replace(strings.ToLower(value))
+	return strings.Map(func(char rune) rune {
+		switch char {
+		case ' ', '\t', '-', '_', '　':
+			return -1
+		}
+		return char
+	}, value)
+}
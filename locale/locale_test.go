@@ -0,0 +1,253 @@
+package locale
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/models/timeofday"
+	"github.com/peake100/turnup-go/values"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Returns the name every in-game phase reports from PatternPhase.Name().
+func gamePhaseNames(t *testing.T) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, pattern := range models.PATTERNSGAME {
+		phases, err := pattern.Progression(models.NewTicker(100, models.UNKNOWN, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, phase := range phases {
+			if !seen[phase.Name()] {
+				seen[phase.Name()] = true
+				names = append(names, phase.Name())
+			}
+		}
+	}
+	return names
+}
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	for tag, expected := range map[string]*Catalog{
+		"en":    English,
+		"EN-us": English,
+		"ja_JP": Japanese,
+		"fr-CA": French,
+		"de":    German,
+		"es-MX": Spanish,
+	} {
+		catalog, err := Lookup(tag)
+		assert.NoError(err, tag)
+		assert.Same(expected, catalog, tag)
+	}
+
+	_, err := Lookup("tlh")
+	assert.Equal(errs.ErrUnknownLanguage, err)
+
+	assert.Equal([]string{"en", "ja", "fr", "de", "es"}, Languages())
+}
+
+// Every catalog should have a name for everything, and read back every name it writes.
+func TestCatalogsRoundTrip(t *testing.T) {
+	phases := gamePhaseNames(t)
+
+	for _, catalog := range catalogs {
+		catalog := catalog
+		t.Run(catalog.Language(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			for _, pattern := range models.PATTERNS {
+				name := catalog.Pattern(pattern)
+				assert.NotEqual(pattern.String(), name)
+
+				parsed, err := catalog.ParsePattern(name)
+				assert.NoError(err, name)
+				assert.Equal(pattern, parsed, name)
+			}
+
+			for _, phase := range phases {
+				name := catalog.Phase(phase)
+				parsed, err := catalog.ParsePhase(name)
+				assert.NoError(err, name)
+				assert.Equal(phase, parsed, name)
+			}
+
+			for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+				parsed, err := catalog.ParseWeekday(catalog.Weekday(weekday))
+				assert.NoError(err)
+				assert.Equal(weekday, parsed)
+			}
+
+			for _, tod := range []timeofday.ToD{timeofday.AM, timeofday.PM} {
+				parsed, err := catalog.ParseToD(catalog.ToD(tod))
+				assert.NoError(err)
+				assert.Equal(tod, parsed)
+			}
+
+			for period := 0; period < values.PricePeriodCount; period++ {
+				name := catalog.Period(models.PricePeriod(period))
+				parsed, err := catalog.ParsePeriod(name)
+				assert.NoError(err, name)
+				assert.Equal(models.PricePeriod(period), parsed, name)
+			}
+		})
+	}
+}
+
+func TestCatalogFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Small Spike", English.Pattern(models.SMALLSPIKE))
+	assert.Equal("small spike", English.Phase("small spike"))
+	assert.Equal("sharp increase", English.Phase("sharp increase"))
+	assert.Equal("constant decrease", English.Phase(models.PhaseConstantDecrease))
+	assert.Equal("forte hausse", French.Phase("sharp increase"))
+	assert.Equal("Tuesday PM", English.Period(3))
+
+	assert.Equal("跳ね大型", Japanese.Pattern(models.BIGSPIKE))
+	assert.Equal("火曜日午後", Japanese.Period(3))
+	assert.Equal("mardi après-midi", French.Period(3))
+	assert.Equal("Dienstag Nachmittag", German.Period(3))
+	assert.Equal("martes por la tarde", Spanish.Period(3))
+
+	// Things the catalogs don't know about are left alone.
+	assert.Equal("PricePattern(7)", German.Pattern(7))
+	assert.Equal("custom phase", French.Phase("custom phase"))
+	assert.Equal("PricePeriod(12)", Spanish.Period(12))
+}
+
+func TestCatalogParse(t *testing.T) {
+	assert := assert.New(t)
+
+	pattern, err := French.ParsePattern("grand-pic")
+	assert.NoError(err)
+	assert.Equal(models.BIGSPIKE, pattern)
+
+	// Accents are optional.
+	pattern, err = French.ParsePattern("DECROISSANT")
+	assert.NoError(err)
+	assert.Equal(models.DECREASING, pattern)
+
+	pattern, err = Japanese.ParsePattern("3期型")
+	assert.NoError(err)
+	assert.Equal(models.BIGSPIKE, pattern)
+
+	// English is always understood.
+	pattern, err = Spanish.ParsePattern("smallspike")
+	assert.NoError(err)
+	assert.Equal(models.SMALLSPIKE, pattern)
+
+	weekday, err := German.ParseWeekday("donnerstag")
+	assert.NoError(err)
+	assert.Equal(time.Thursday, weekday)
+
+	period, err := Spanish.ParsePeriod("Miercoles por la manana")
+	assert.NoError(err)
+	assert.Equal(models.PricePeriod(4), period)
+
+	phase, err := German.ParsePhase("Anhaltender Rückgang")
+	assert.NoError(err)
+	assert.Equal(models.PhaseConstantDecrease, phase)
+
+	// Other languages are not.
+	_, err = German.ParsePattern("grand pic")
+	assert.Equal(errs.ErrPatternStringValue, err)
+	_, err = English.ParsePhase("急上昇")
+	assert.Equal(errs.ErrPhaseStringValue, err)
+	_, err = French.ParseWeekday("lunes")
+	assert.Equal(errs.ErrWeekdayStringValue, err)
+	_, err = Japanese.ParseToD("tarde")
+	assert.Equal(errs.ErrTimeOfDayStringValue, err)
+	_, err = English.ParsePeriod("Sunday AM")
+	assert.Equal(errs.ErrPricePeriodStringValue, err)
+}
+
+func TestParsePatternAnyLanguage(t *testing.T) {
+	assert := assert.New(t)
+
+	for value, expected := range map[string]models.PricePattern{
+		"big spike":     models.BIGSPIKE,
+		"波型":            models.FLUCTUATING,
+		"petit pic":     models.SMALLSPIKE,
+		"Fallend":       models.DECREASING,
+		"pico pequeño":  models.SMALLSPIKE,
+		"desconocido":   models.UNKNOWN,
+		"Kleine Spitze": models.SMALLSPIKE,
+	} {
+		pattern, err := ParsePattern(value)
+		assert.NoError(err, value)
+		assert.Equal(expected, pattern, value)
+	}
+
+	_, err := ParsePattern("blah")
+	assert.Equal(errs.ErrPatternStringValue, err)
+}
+
+// Every catalog other than English should write every explanation message itself.
+func TestCatalogsExplainMessages(t *testing.T) {
+	for _, catalog := range catalogs[1:] {
+		last := models.ExplainLessLikelyPatterns
+		for message := models.ExplainChance; message <= last; message++ {
+			_, ok := catalog.messages.explain[message]
+			assert.True(t, ok, "%v: message %v", catalog.Language(), message)
+		}
+	}
+}
+
+func TestExplainIn(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := models.NewTicker(100, models.UNKNOWN, 4)
+	for i, price := range []int{88, 84, 80, 125, 190} {
+		ticker.Prices[i] = price
+	}
+	prediction, err := (&models.Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	explanation := prediction.ExplainIn(French)
+	assert.Equal(
+		"Grand pic est à 100 % car mercredi matin a augmenté de 52 % par rapport à "+
+			"mardi après-midi, ce qui correspond à la phase de forte hausse\n"+
+			"Fluctuant est à 0 % car lundi matin était à 88 clochettes, ce qui est "+
+			"impossible pour les phases de légère baisse ou légère hausse\n"+
+			"Décroissant est à 0 % car mardi après-midi a augmenté de 56 % par "+
+			"rapport à mardi matin, ce qui est impossible pour la phase de baisse "+
+			"continue (74-78 clochettes)\n"+
+			"Petit pic est à 0 % car mercredi matin a augmenté de 52 % par rapport à "+
+			"mardi après-midi, ce qui est impossible pour la phase de petit pic "+
+			"(90-140 clochettes)",
+		explanation.String(),
+	)
+
+	// The English catalog writes the same explanation as Explain, but with readable
+	// phase names.
+	assert.Equal(
+		strings.ReplaceAll(
+			prediction.Explain().String(),
+			models.PhaseConstantDecrease,
+			English.Phase(models.PhaseConstantDecrease),
+		),
+		prediction.ExplainIn(English).String(),
+	)
+
+	// Phase names stay the ones the phases report, so they can be looked up.
+	bigSpike := explanation.Patterns[0]
+	assert.Equal(models.BIGSPIKE, bigSpike.Pattern)
+	assert.Equal([]string{models.PhaseSharpIncrease}, bigSpike.Key.Phases)
+	if assert.NotEmpty(bigSpike.Weeks) {
+		assert.Contains(
+			bigSpike.Weeks[0].String(),
+			"forte hausse (de mardi après-midi à mercredi après-midi)",
+		)
+	}
+}
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"sort"
+	"math/bits"
 )
 
 type hasPriceRange interface {
@@ -9,6 +9,7 @@ type hasPriceRange interface {
 	MinPeriods() []PricePeriod
 	GuaranteedPeriods() []PricePeriod
 	MaxPeriods() []PricePeriod
+	periodSets() (min periodSet, guaranteed periodSet, max periodSet)
 }
 
 type hasProbability interface {
@@ -21,6 +22,30 @@ type hasFullAnalysis interface {
 	hasProbability
 }
 
+// A set of price periods. Bit ``n`` is set if price period ``n`` is in the set. We use
+// a bitset over a map since there are only 12 price periods, and every potential week
+// and pattern keeps three of these sets.
+type periodSet uint16
+
+func (set *periodSet) add(period PricePeriod) {
+	*set |= 1 << uint(period)
+}
+
+func (set periodSet) has(period PricePeriod) bool {
+	return set&(1<<uint(period)) != 0
+}
+
+// Returns the periods in the set, in order.
+func (set periodSet) periods() []PricePeriod {
+	periods := make([]PricePeriod, 0, bits.OnesCount16(uint16(set)))
+	for remaining := set; remaining != 0; remaining &= remaining - 1 {
+		periods = append(
+			periods, PricePeriod(bits.TrailingZeros16(uint16(remaining))),
+		)
+	}
+	return periods
+}
+
 // Information about the min and max prices over the 12 price periods of the week.
 type PriceSeries struct {
 	pricesVal
@@ -29,84 +54,58 @@ type PriceSeries struct {
 	currentPeriod PricePeriod
 	currentPrice  int
 
-	// We want to implement this as a map so we don't double-add price periods.
-	// We're going to use it as a set
-	minPeriodsSet        map[PricePeriod]interface{}
-	guaranteedPeriodsSet map[PricePeriod]interface{}
-	maxPeriodsSet        map[PricePeriod]interface{}
-
-	minPeriodsCached        []PricePeriod
-	guaranteedPeriodsCached []PricePeriod
-	maxPeriodsCached        []PricePeriod
-}
-
-func (prices *PriceSeries) createPeriodCache(
-	periodSet map[PricePeriod]interface{},
-) []PricePeriod {
-	newCache := make([]PricePeriod, len(periodSet))
-	i := 0
-	for key := range periodSet {
-		newCache[i] = key
-		i++
-	}
-
-	// Sort the slice. Maps are not sorted so we have to sort them here.
-	sort.SliceStable(
-		newCache,
-		func(i, j int) bool {
-			return newCache[i] < newCache[j]
-		},
-	)
-
-	return newCache
+	// The price periods each of our prices occur in. The period lists are made from
+	// these when asked for rather than kept, so reading a series never changes it and
+	// it is safe to read from many goroutines at once.
+	minPeriodsSet        periodSet
+	guaranteedPeriodsSet periodSet
+	maxPeriodsSet        periodSet
 }
 
-// The price periods that the absolute minimum price might occur
+// The price periods that the absolute minimum price might occur. A new slice is made
+// on each call, so it is safe to change, but should be kept rather than asked for in a
+// loop.
 func (prices *PriceSeries) MinPeriods() []PricePeriod {
-	if prices.minPeriodsCached == nil {
-		prices.minPeriodsCached = prices.createPeriodCache(prices.minPeriodsSet)
-	}
-	return prices.minPeriodsCached
+	return prices.minPeriodsSet.periods()
 }
 
 // The PricePeriods that this minimum guaranteed price might occur. On PotentialWeeks,
 // this will always be a single value, but on PotentialPatterns and Predictions, every
-// possible day the minimum guaranteed price *might* occur is used.
+// possible day the minimum guaranteed price *might* occur is used. Like MinPeriods(),
+// a new slice is made on each call.
 func (prices *PriceSeries) GuaranteedPeriods() []PricePeriod {
-	if prices.guaranteedPeriodsCached == nil {
-		prices.guaranteedPeriodsCached = prices.createPeriodCache(
-			prices.guaranteedPeriodsSet,
-		)
-	}
-	return prices.guaranteedPeriodsCached
+	return prices.guaranteedPeriodsSet.periods()
 }
 
 // The PricePeriods that this maximum potential price might occur. On PotentialWeeks,
 // this will always be a single value, but on PotentialPatterns and Predictions, every
-// possible day the maximum potential price *might* occur is used.
+// possible day the maximum potential price *might* occur is used. Like MinPeriods(),
+// a new slice is made on each call.
 func (prices *PriceSeries) MaxPeriods() []PricePeriod {
-	if prices.maxPeriodsCached == nil {
-		prices.maxPeriodsCached = prices.createPeriodCache(prices.maxPeriodsSet)
-	}
-	return prices.maxPeriodsCached
+	return prices.maxPeriodsSet.periods()
+}
+
+// Returns the period sets behind MinPeriods(), GuaranteedPeriods() and MaxPeriods(),
+// so other series can be merged in without building the slices.
+func (prices *PriceSeries) periodSets() (
+	min periodSet, guaranteed periodSet, max periodSet,
+) {
+	return prices.minPeriodsSet, prices.guaranteedPeriodsSet, prices.maxPeriodsSet
 }
 
 func (prices *PriceSeries) clearPeriods(
 	guaranteedUpdated bool, maxUpdated bool, minUpdated bool,
 ) {
 	// If the value was updated, we have a new min/max, so we need to clear the
-	// map
+	// set
 	if minUpdated {
-		prices.minPeriodsSet = make(map[PricePeriod]interface{})
-		prices.minPeriodsCached = nil
+		prices.minPeriodsSet = 0
 	}
 	if guaranteedUpdated {
-		prices.guaranteedPeriodsSet = make(map[PricePeriod]interface{})
-		prices.guaranteedPeriodsCached = nil
+		prices.guaranteedPeriodsSet = 0
 	}
 	if maxUpdated {
-		prices.maxPeriodsSet = make(map[PricePeriod]interface{})
-		prices.maxPeriodsCached = nil
+		prices.maxPeriodsSet = 0
 	}
 }
 
@@ -153,23 +152,15 @@ func (prices *PriceSeries) updatePriceRangeFromPrices(
 	// Now add the price period to the set if it was updated OR if it's equal to our
 	// current value, as that means it's another high or low point
 	if minUpdated || other.MinPrice() == prices.minPrice {
-		prices.minPeriodsSet[period] = nil
+		prices.minPeriodsSet.add(period)
 	}
 
 	if guaranteedUpdated || other.GuaranteedPrice() == prices.guaranteedPrice {
-		prices.guaranteedPeriodsSet[period] = nil
+		prices.guaranteedPeriodsSet.add(period)
 	}
 
 	if maxUpdated || other.MaxPrice() == prices.maxPrice {
-		prices.maxPeriodsSet[period] = nil
-	}
-}
-
-func (prices *PriceSeries) addPeriodsToSet(
-	periods []PricePeriod, set map[PricePeriod]interface{},
-) {
-	for _, pricePeriod := range periods {
-		set[pricePeriod] = nil
+		prices.maxPeriodsSet.add(period)
 	}
 }
 
@@ -180,18 +171,19 @@ func (prices *PriceSeries) updatePriceRangeFromOther(other hasPriceRange) {
 	)
 	prices.clearPeriods(guaranteedUpdated, maxUpdated, minUpdated)
 
-	// Now add the price period to the set if it was updated OR if it's equal to our
+	// Now add the price periods to the set if it was updated OR if it's equal to our
 	// current value, as that means it's another high or low point
+	otherMin, otherGuaranteed, otherMax := other.periodSets()
 	if minUpdated || other.MinPrice() == prices.minPrice {
-		prices.addPeriodsToSet(other.MinPeriods(), prices.minPeriodsSet)
+		prices.minPeriodsSet |= otherMin
 	}
 
 	if guaranteedUpdated || other.GuaranteedPrice() == prices.guaranteedPrice {
-		prices.addPeriodsToSet(other.GuaranteedPeriods(), prices.guaranteedPeriodsSet)
+		prices.guaranteedPeriodsSet |= otherGuaranteed
 	}
 
 	if maxUpdated || other.MaxPrice() == prices.maxPrice {
-		prices.addPeriodsToSet(other.MaxPeriods(), prices.maxPeriodsSet)
+		prices.maxPeriodsSet |= otherMax
 	}
 }
 
@@ -220,7 +212,14 @@ func (analysis *Analysis) setChance(value float64) {
 }
 
 func NewAnalysis(ticker *PriceTicker) *Analysis {
-	return &Analysis{
+	analysis := new(Analysis)
+	analysis.setup(ticker)
+	return analysis
+}
+
+// Sets up an analysis that was allocated as part of something else.
+func (analysis *Analysis) setup(ticker *PriceTicker) {
+	*analysis = Analysis{
 		PriceSeries: PriceSeries{},
 		// We need to set up the future price series for this.
 		Future: PriceSeries{
@@ -48,6 +48,23 @@ func (prices *pricesVal) PriceChance(price int) float64 {
 	}
 }
 
+// Returns the total chance of this price range resulting in any price from ``low`` to
+// ``high`` (inclusive). A bound of 0 is open-ended.
+func (prices *pricesVal) rangeChance(low int, high int) float64 {
+	if low == 0 || low < prices.guaranteedPrice {
+		low = prices.guaranteedPrice
+	}
+	if high == 0 || high > prices.maxPrice {
+		high = prices.maxPrice
+	}
+
+	var chance float64
+	for price := low; price <= high; price++ {
+		chance += prices.PriceChance(price)
+	}
+	return chance
+}
+
 func (prices *pricesVal) updateMin(value int) (updated bool) {
 	updated = (prices.minPrice == 0 || value < prices.minPrice) && value != 0
 
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Benchmarks working out every phase permutation of every pattern, and the price
+// periods of each one, without weighing them against the ticker.
+func BenchmarkPhaseGeneration(b *testing.B) {
+	for _, fixture := range benchmarkFixtures() {
+		ticker := fixture.Ticker
+		// The generator needs a purchase price to work from.
+		if ticker.PurchasePrice == 0 {
+			ticker.PurchasePrice = 100
+		}
+
+		b.Run(fixture.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			brancher := &phaseBrancher{
+				onComplete: func(patternPhases []PatternPhase) {
+					var period PricePeriod
+					for _, phase := range patternPhases {
+						for subPeriod := 0; subPeriod < phase.Length(); subPeriod++ {
+							phase.PotentialPeriod(period, subPeriod)
+							period++
+						}
+					}
+				},
+			}
+
+			for i := 0; i < b.N; i++ {
+				for _, definition := range defaultPatterns.Definitions() {
+					brancher.branchPhases(definition.PhaseProgression(ticker))
+				}
+			}
+		})
+	}
+}
+
+// Benchmarks calculating the chance of every potential week once they have been
+// worked out.
+func BenchmarkCalculateChances(b *testing.B) {
+	for _, fixture := range benchmarkFixtures() {
+		ticker := fixture.Ticker
+
+		b.Run(fixture.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				predictor := &Predictor{Ticker: ticker}
+				if err := predictor.predictPatterns(); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				predictor.calculateChances(predictor.result)
+			}
+		})
+	}
+}
+
+// Every fixture should be a ticker the predictor can make a prediction for.
+func TestFixturesPredict(t *testing.T) {
+	for _, fixture := range benchmarkFixtures() {
+		prediction, err := (&Predictor{Ticker: fixture.Ticker}).Predict()
+		assert.NoError(t, err, fixture.Name)
+		assert.NotNil(t, prediction, fixture.Name)
+	}
+}
+
+func BenchmarkPredict(b *testing.B) {
+	for _, fixture := range benchmarkFixtures() {
+		ticker := fixture.Ticker
+
+		b.Run(fixture.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := (&Predictor{Ticker: ticker}).Predict(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPredictBatch(b *testing.B) {
+	fixtures := benchmarkFixtures()
+	tickers := make([]*PriceTicker, len(fixtures))
+	for i, fixture := range fixtures {
+		tickers[i] = fixture.Ticker
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		PredictBatch(tickers, PredictOptions{})
+	}
+}
@@ -0,0 +1,142 @@
+package models
+
+// Implements the "chance of at least X bells" queries for a single potential week.
+// These are answered from the week's price bands.
+type weekChanceQuery func(week *PotentialWeek) float64
+
+// Weights a per-week query by the chance of each week. If every week has a chance of
+// 0 (which can happen when chances are rounded), each week is weighted equally.
+func weighWeekQuery(weeks []*PotentialWeek, query weekChanceQuery) float64 {
+	totalChance := 0.0
+	for _, week := range weeks {
+		totalChance += week.Chance()
+	}
+
+	var chance, totalWeight float64
+	for _, week := range weeks {
+		weight := week.Chance()
+		if totalChance <= 0 {
+			weight = 1
+		}
+		chance += query(week) * weight
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0
+	}
+
+	chance /= totalWeight
+	// Guard against floating point drift pushing us over 1.
+	if chance > 1 {
+		chance = 1
+	}
+	return chance
+}
+
+func queryAtLeast(price int) weekChanceQuery {
+	return func(week *PotentialWeek) float64 {
+		return week.ChanceAtLeast(price)
+	}
+}
+
+func queryAtLeastInPeriod(price int, period PricePeriod) weekChanceQuery {
+	return func(week *PotentialWeek) float64 {
+		return week.ChanceAtLeastInPeriod(price, period)
+	}
+}
+
+func queryAtLeastBy(price int, period PricePeriod) weekChanceQuery {
+	return func(week *PotentialWeek) float64 {
+		return week.ChanceAtLeastBy(price, period)
+	}
+}
+
+// The chance from 0.0-1.0 that the highest price from the current price period to the
+// end of the week will be at least ``price``.
+func (week *PotentialWeek) ChanceAtLeast(price int) float64 {
+	return week.Bands.FutureMax().ChanceAtLeast(price)
+}
+
+// The chance from 0.0-1.0 that the price will be at least ``price`` during
+// ``period``.
+func (week *PotentialWeek) ChanceAtLeastInPeriod(
+	price int, period PricePeriod,
+) float64 {
+	return week.Bands.Period(period).ChanceAtLeast(price)
+}
+
+// The chance from 0.0-1.0 that a price of at least ``price`` will occur at some point
+// between the current price period and ``period`` (inclusive). Returns 0 if
+// ``period`` is before the current price period. Like PriceBands.WeeklyMax, this
+// accounts for the prices of the week being tied together by its phases, and is an
+// approximation when the prediction was made with an ErrorRate.
+func (week *PotentialWeek) ChanceAtLeastBy(price int, period PricePeriod) float64 {
+	start := week.Bands.futureStart()
+	if period < start {
+		return 0
+	}
+	return newWeekMaxDistribution(week, &week.Bands.periods, start, period).
+		ChanceAtLeast(price)
+}
+
+// The chance from 0.0-1.0 that the highest price from the current price period to the
+// end of the week will be at least ``price``, assuming this pattern occurs.
+func (pattern *PotentialPattern) ChanceAtLeast(price int) float64 {
+	return weighWeekQuery(pattern.PotentialWeeks, queryAtLeast(price))
+}
+
+// The chance from 0.0-1.0 that the price will be at least ``price`` during
+// ``period``, assuming this pattern occurs.
+func (pattern *PotentialPattern) ChanceAtLeastInPeriod(
+	price int, period PricePeriod,
+) float64 {
+	return weighWeekQuery(
+		pattern.PotentialWeeks, queryAtLeastInPeriod(price, period),
+	)
+}
+
+// The chance from 0.0-1.0 that a price of at least ``price`` will occur at some point
+// between the current price period and ``period`` (inclusive), assuming this pattern
+// occurs. Worked out for each potential week the same way as
+// PotentialWeek.ChanceAtLeastBy.
+func (pattern *PotentialPattern) ChanceAtLeastBy(
+	price int, period PricePeriod,
+) float64 {
+	return weighWeekQuery(pattern.PotentialWeeks, queryAtLeastBy(price, period))
+}
+
+// Every potential week across all patterns.
+func (prediction *Prediction) potentialWeeks() []*PotentialWeek {
+	var weeks []*PotentialWeek
+	for _, potentialPattern := range prediction.Patterns {
+		weeks = append(weeks, potentialPattern.PotentialWeeks...)
+	}
+	return weeks
+}
+
+// The chance from 0.0-1.0 that the highest price from the current price period to the
+// end of the week will be at least ``price``.
+func (prediction *Prediction) ChanceAtLeast(price int) float64 {
+	return weighWeekQuery(prediction.potentialWeeks(), queryAtLeast(price))
+}
+
+// The chance from 0.0-1.0 that the price will be at least ``price`` during
+// ``period``.
+func (prediction *Prediction) ChanceAtLeastInPeriod(
+	price int, period PricePeriod,
+) float64 {
+	return weighWeekQuery(
+		prediction.potentialWeeks(), queryAtLeastInPeriod(price, period),
+	)
+}
+
+// The chance from 0.0-1.0 that a price of at least ``price`` will occur at some point
+// between the current price period and ``period`` (inclusive). Returns 0 if
+// ``period`` is before the current price period. Worked out for each potential week
+// the same way as PotentialWeek.ChanceAtLeastBy.
+func (prediction *Prediction) ChanceAtLeastBy(
+	price int, period PricePeriod,
+) float64 {
+	return weighWeekQuery(prediction.potentialWeeks(), queryAtLeastBy(price, period))
+}
@@ -0,0 +1,86 @@
+package models
+
+// Constraint conditions a prediction on something we know about the week that is not
+// a price, like "prices went up on Tuesday afternoon" or "no spike happened before
+// Thursday". Constraints are applied to each potential week after its chance has been
+// worked out from the ticker's prices, and the prediction is re-normalized afterwards.
+type Constraint interface {
+	// Returns the weight to multiply the week's chance by, from 0.0 to 1.0. A weight
+	// of 0 removes the week from the prediction entirely.
+	Weight(week *PotentialWeek) float64
+}
+
+// ConstraintFunc lets a plain function be used as a Constraint.
+type ConstraintFunc func(week *PotentialWeek) float64
+
+func (constraint ConstraintFunc) Weight(week *PotentialWeek) float64 {
+	return constraint(week)
+}
+
+// Converts a true / false check into a weight.
+func constraintWeight(passes bool) float64 {
+	if passes {
+		return 1
+	}
+	return 0
+}
+
+// Removes any week that has a spike of any kind before ``period``.
+func NoSpikeBefore(period PricePeriod) Constraint {
+	return ConstraintFunc(func(week *PotentialWeek) float64 {
+		spike := week.Spikes.Any()
+		return constraintWeight(!spike.Has() || spike.Start() >= period)
+	})
+}
+
+// Removes any week of ``pattern``.
+func ExcludePattern(pattern PricePattern) Constraint {
+	return ConstraintFunc(func(week *PotentialWeek) float64 {
+		return constraintWeight(week.Pattern != pattern)
+	})
+}
+
+// Removes any week where ``predicate`` returns false for the potential price period at
+// ``period``.
+func PeriodPredicate(
+	period PricePeriod, predicate func(potential *PotentialPricePeriod) bool,
+) Constraint {
+	return ConstraintFunc(func(week *PotentialWeek) float64 {
+		if period < 0 || int(period) >= len(week.Prices) {
+			return 0
+		}
+		return constraintWeight(predicate(week.Prices[period]))
+	})
+}
+
+// Weights each week by the chance that the price in ``period`` was higher than the
+// price in the period before it. The two periods are treated as independent.
+func PriceRose(period PricePeriod) Constraint {
+	return ConstraintFunc(func(week *PotentialWeek) float64 {
+		if period < 1 || int(period) >= len(week.Prices) {
+			return 0
+		}
+		return chanceGreater(week.Bands.Period(period), week.Bands.Period(period-1))
+	})
+}
+
+// Weights each week by the chance that the price in ``period`` was lower than the
+// price in the period before it. The two periods are treated as independent.
+func PriceFell(period PricePeriod) Constraint {
+	return ConstraintFunc(func(week *PotentialWeek) float64 {
+		if period < 1 || int(period) >= len(week.Prices) {
+			return 0
+		}
+		return chanceGreater(week.Bands.Period(period-1), week.Bands.Period(period))
+	})
+}
+
+// The chance a price drawn from ``dist`` is higher than an independent price drawn
+// from ``other``.
+func chanceGreater(dist *PriceDistribution, other *PriceDistribution) float64 {
+	var chance float64
+	for i, priceChance := range dist.chances {
+		chance += priceChance * other.ChanceAtMost(dist.minPrice+i-1)
+	}
+	return chance
+}
@@ -0,0 +1,156 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func predictConstrained(
+	t *testing.T, constraints ...Constraint,
+) *Prediction {
+	ticker := NewTicker(100, DECREASING, 1)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+
+	prediction, err := (&Predictor{
+		Ticker:         ticker,
+		PredictOptions: PredictOptions{Constraints: constraints},
+	}).Predict()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0.0
+	for _, pattern := range prediction.Patterns {
+		total += pattern.Chance()
+	}
+	assert.InDelta(t, 1, total, 0.0005, "pattern chances renormalized")
+
+	return prediction
+}
+
+func TestConstraintNoSpikeBefore(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction := predictConstrained(t, NoSpikeBefore(6))
+	assert.GreaterOrEqual(int(prediction.Spikes.Any().Start()), 6)
+	for period := 0; period < 6; period++ {
+		assert.Equal(0.0, prediction.Spikes.Any().Breakdown()[period])
+	}
+}
+
+func TestConstraintExcludePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction := predictConstrained(t, ExcludePattern(DECREASING))
+	assert.Equal(0.0, prediction.Patterns[DECREASING].Chance())
+	assert.Len(prediction.Patterns[DECREASING].PotentialWeeks, 0)
+}
+
+func TestConstraintPriceRose(t *testing.T) {
+	assert := assert.New(t)
+
+	// Prices never rise on a decreasing week.
+	prediction := predictConstrained(t, PriceRose(2))
+	assert.Equal(0.0, prediction.Patterns[DECREASING].Chance())
+
+	// And always fall.
+	prediction = predictConstrained(t, PriceFell(2))
+	assert.Greater(prediction.Patterns[DECREASING].Chance(), 0.0)
+}
+
+func TestConstraintPeriodPredicate(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction := predictConstrained(
+		t,
+		PeriodPredicate(4, func(potential *PotentialPricePeriod) bool {
+			return potential.Spikes.Big().Has()
+		}),
+	)
+
+	assert.Equal(1.0, prediction.Patterns[BIGSPIKE].Chance())
+	assert.InDelta(1, prediction.Spikes.Big().Breakdown()[4], 0.0005)
+}
+
+func TestConstraintFuncWeight(t *testing.T) {
+	assert := assert.New(t)
+
+	unweighted := predictConstrained(t)
+	weighted := predictConstrained(t, ConstraintFunc(func(week *PotentialWeek) float64 {
+		if week.Pattern == FLUCTUATING {
+			return 0.5
+		}
+		return 1
+	}))
+
+	ratio := func(prediction *Prediction) float64 {
+		return prediction.Patterns[FLUCTUATING].Chance() /
+			prediction.Patterns[SMALLSPIKE].Chance()
+	}
+	assert.InDelta(ratio(unweighted)/2, ratio(weighted), 0.01)
+}
+
+func TestConstraintExcludeAll(t *testing.T) {
+	ticker := NewTicker(100, DECREASING, 0)
+	_, err := (&Predictor{
+		Ticker: ticker,
+		PredictOptions: PredictOptions{
+			Constraints: []Constraint{
+				ConstraintFunc(func(*PotentialWeek) float64 { return 0 }),
+			},
+		},
+	}).Predict()
+	assert.Equal(t, errs.ErrConstraintsExcludeAll, err)
+}
+
+func TestChanceGreater(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1.0, chanceGreater(newPointDistribution(5), newPointDistribution(4)))
+	assert.Equal(0.0, chanceGreater(newPointDistribution(4), newPointDistribution(4)))
+
+	even := &PriceDistribution{minPrice: 1, chances: []float64{0.5, 0.5}}
+	assert.Equal(0.25, chanceGreater(even, even))
+}
+
+func TestConstraintWeightFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	// Weights this small leave every week with a width of 0, so the chances fall back
+	// to counting the weeks each pattern has left.
+	predict := func(fluctuatingWeight float64) *Predictor {
+		predictor := &Predictor{
+			Ticker: NewTicker(100, DECREASING, 0),
+			PredictOptions: PredictOptions{
+				Constraints: []Constraint{
+					ConstraintFunc(func(week *PotentialWeek) float64 {
+						if week.Pattern == FLUCTUATING {
+							return fluctuatingWeight * math.SmallestNonzeroFloat64
+						}
+						return math.SmallestNonzeroFloat64
+					}),
+				},
+			},
+		}
+		if _, err := predictor.Predict(); err != nil {
+			t.Fatal(err)
+		}
+		return predictor
+	}
+
+	ratio := func(predictor *Predictor) float64 {
+		return predictor.result.Patterns[FLUCTUATING].Chance() /
+			predictor.result.Patterns[SMALLSPIKE].Chance()
+	}
+
+	unweighted := predict(1)
+	weighted := predict(2)
+	assert.Greater(unweighted.totalWidth, 0.0, "fell back to pattern count")
+	assert.InDelta(ratio(unweighted)*2, ratio(weighted), 0.01)
+}
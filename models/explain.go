@@ -0,0 +1,559 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/values"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Why a pattern or potential week has the chance it does.
+type ExplanationReason int
+
+const (
+	// A known price changed the chance the most. The explanation's Key says which.
+	ReasonPrice ExplanationReason = iota
+	// No known price changed the chance, so it's down to last week's pattern.
+	ReasonPrior
+	// The pattern can't follow last week's pattern.
+	ReasonPreviousPattern
+	// Every week of the pattern was removed by the prediction's constraints.
+	ReasonConstraints
+)
+
+// Evidence is what a single known price of the ticker did to the chance of a pattern
+// or potential week.
+type Evidence struct {
+	// The price period of the price.
+	Period PricePeriod
+	// The lowest and highest price the ticker has for the period. The same for an
+	// exact price. A bound of 0 is open-ended.
+	Low  int
+	High int
+
+	// Whether the price and the closest exact price before it are both known, so we
+	// can say how much it changed.
+	HasChange bool
+	// The period of the earlier price.
+	ChangeFrom PricePeriod
+	// How much the price changed since ChangeFrom: 0.4 if it rose 40%.
+	Change float64
+
+	// The name of the phase the period most likely falls in. A price that ruled out a
+	// pattern lists every phase the pattern could have been in.
+	Phases []string
+	// The lowest and highest price the phase could have had in this period. 0 if
+	// there is more than one phase.
+	PhaseMin int
+	PhaseMax int
+
+	// How many times more likely the price is for the pattern or week than for the
+	// other patterns or weeks that are still possible. Under 1 if the price is less
+	// likely, and 0 if the price ruled it out.
+	LikelihoodRatio float64
+
+	// Whether LikelihoodRatio compares a week to the other weeks rather than a pattern
+	// to the other patterns.
+	againstWeeks bool
+	// The language the evidence is written in.
+	language ExplainLanguage
+}
+
+// Describes the price, and how it fits the phase: "Tuesday PM rose 40% over Tuesday
+// AM, which fits the sharp increase phase".
+func (evidence *Evidence) String() string {
+	language := explainLanguageOrEnglish(evidence.language)
+	price := evidence.describePrice(language)
+	phase := evidence.describePhases(language)
+
+	switch {
+	case evidence.LikelihoodRatio == 0 && evidence.PhaseMax == 0:
+		return language.Message(ExplainCantMake, price, phase)
+	case evidence.LikelihoodRatio == 0:
+		return language.Message(
+			ExplainCantMakeRange, price, phase, evidence.PhaseMin, evidence.PhaseMax,
+		)
+	case evidence.LikelihoodRatio >= 1:
+		return language.Message(ExplainFits, price, phase)
+	case evidence.againstWeeks:
+		return language.Message(ExplainLessLikelyWeeks, price, phase)
+	default:
+		return language.Message(ExplainLessLikelyPatterns, price, phase)
+	}
+}
+
+// "the mild decrease or mild increase phases"
+func (evidence *Evidence) describePhases(language ExplainLanguage) string {
+	if len(evidence.Phases) == 0 {
+		return language.Message(ExplainPhase, "")
+	}
+
+	names := language.Phase(evidence.Phases[0])
+	for _, phase := range evidence.Phases[1:] {
+		names = language.Message(ExplainPhaseOr, names, language.Phase(phase))
+	}
+	if len(evidence.Phases) > 1 {
+		return language.Message(ExplainPhases, names)
+	}
+	return language.Message(ExplainPhase, names)
+}
+
+func (evidence *Evidence) describePrice(language ExplainLanguage) string {
+	period := language.Period(evidence.Period)
+	switch {
+	case evidence.HasChange && evidence.Change == 0:
+		return language.Message(ExplainStayed, period, evidence.Low)
+	case evidence.HasChange:
+		message := ExplainRose
+		if evidence.Change < 0 {
+			message = ExplainFell
+		}
+		return language.Message(
+			message,
+			period,
+			math.Abs(evidence.Change)*100,
+			language.Period(evidence.ChangeFrom),
+		)
+	case evidence.Low == evidence.High:
+		return language.Message(ExplainWas, period, evidence.Low)
+	case evidence.High == 0:
+		return language.Message(ExplainAtLeast, period, evidence.Low)
+	case evidence.Low == 0:
+		return language.Message(ExplainAtMost, period, evidence.High)
+	default:
+		return language.Message(ExplainBetween, period, evidence.Low, evidence.High)
+	}
+}
+
+// PhaseSpan is a run of price periods a potential week spends in a single phase.
+type PhaseSpan struct {
+	Phase string
+	Start PricePeriod
+	// The last period of the phase, inclusive.
+	End PricePeriod
+
+	language ExplainLanguage
+}
+
+func (span PhaseSpan) String() string {
+	language := explainLanguageOrEnglish(span.language)
+	phase := language.Phase(span.Phase)
+	if span.Start == span.End {
+		return language.Message(ExplainSpan, phase, language.Period(span.Start))
+	}
+	return language.Message(
+		ExplainSpanRange,
+		phase,
+		language.Period(span.Start),
+		language.Period(span.End),
+	)
+}
+
+// WeekExplanation explains the chance of a potential week.
+type WeekExplanation struct {
+	Week   *PotentialWeek
+	Chance float64
+	// The phases of the week, in order.
+	Phases []PhaseSpan
+	// Either ReasonPrice or ReasonPrior.
+	Reason ExplanationReason
+	// The known price that set this week apart from the others the most. nil if the
+	// reason is not ReasonPrice.
+	Key *Evidence
+
+	language ExplainLanguage
+}
+
+// "12%: steady decrease (Monday AM to Monday PM), sharp increase (Tuesday AM to
+// Wednesday AM) ..., because Tuesday PM rose 40% over Tuesday AM, which fits the sharp
+// increase phase"
+func (explanation *WeekExplanation) String() string {
+	language := explainLanguageOrEnglish(explanation.language)
+	phases := make([]string, len(explanation.Phases))
+	for i, span := range explanation.Phases {
+		phases[i] = span.String()
+	}
+
+	chance := formatChance(language, explanation.Chance)
+	spans := strings.Join(phases, language.Message(ExplainListSeparator))
+	if explanation.Key == nil {
+		return language.Message(ExplainWeek, chance, spans)
+	}
+	return language.Message(ExplainWeekPrice, chance, spans, explanation.Key)
+}
+
+// PatternExplanation explains the chance of a pattern.
+type PatternExplanation struct {
+	Pattern PricePattern
+	// The name of the pattern in the language of the explanation, like "Big Spike".
+	Name   string
+	Chance float64
+	Reason ExplanationReason
+	// The known price that changed the chance of the pattern the most. If the pattern
+	// was ruled out by the ticker's prices, this is the price that did it. nil if the
+	// reason is not ReasonPrice.
+	Key *Evidence
+	// Explanations for each potential week of the pattern, most likely first.
+	Weeks []*WeekExplanation
+
+	language ExplainLanguage
+}
+
+// "Big Spike is 62% because Tuesday PM rose 40% over Tuesday AM, which fits the sharp
+// increase phase"
+func (explanation *PatternExplanation) String() string {
+	language := explainLanguageOrEnglish(explanation.language)
+	chance := formatChance(language, explanation.Chance)
+	switch explanation.Reason {
+	case ReasonPrice:
+		return language.Message(
+			ExplainPatternPrice, explanation.Name, chance, explanation.Key,
+		)
+	case ReasonPreviousPattern:
+		return language.Message(ExplainPatternPreviousPattern, explanation.Name, chance)
+	case ReasonConstraints:
+		return language.Message(ExplainPatternConstraints, explanation.Name, chance)
+	default:
+		return language.Message(ExplainPatternPrior, explanation.Name, chance)
+	}
+}
+
+// Explanation breaks down why a prediction came out the way it did.
+type Explanation struct {
+	// An explanation for each pattern of the prediction, most likely first.
+	Patterns []*PatternExplanation
+}
+
+// Each pattern's explanation, one per line.
+func (explanation *Explanation) String() string {
+	lines := make([]string, len(explanation.Patterns))
+	for i, pattern := range explanation.Patterns {
+		lines[i] = pattern.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Explain works out which of the ticker's prices made each pattern and potential week
+// as likely as it is, and writes it in English.
+//
+// A price's effect on a pattern is measured by how much more or less likely the price
+// is for the pattern than for the other patterns that are still possible. The price
+// that tells the pattern apart from the others the most is the pattern's key price.
+// Weeks are measured the same way, against every other week.
+func (prediction *Prediction) Explain() *Explanation {
+	return prediction.ExplainIn(EnglishExplain)
+}
+
+// ExplainIn is Explain, written in ``language``. The catalogs of the locale package
+// can be passed in to explain a prediction in another language.
+func (prediction *Prediction) ExplainIn(language ExplainLanguage) *Explanation {
+	explainer := newExplainer(prediction, explainLanguageOrEnglish(language))
+
+	explanation := &Explanation{
+		Patterns: make([]*PatternExplanation, len(prediction.Patterns)),
+	}
+	for i, pattern := range prediction.Patterns {
+		explanation.Patterns[i] = explainer.explainPattern(pattern)
+	}
+	sort.SliceStable(explanation.Patterns, func(i, j int) bool {
+		return explanation.Patterns[i].Chance > explanation.Patterns[j].Chance
+	})
+
+	return explanation
+}
+
+// Holds the totals over every week of a prediction that explanations are measured
+// against.
+type explainer struct {
+	ticker   *PriceTicker
+	language ExplainLanguage
+	// Every pattern of the prediction.
+	patterns []*PotentialPattern
+	// The periods the ticker knows a price for.
+	known []PricePeriod
+	// The total prior chance of every week.
+	priorTotal float64
+	// The total prior chance of every week weighted by how likely each period's price
+	// is for the week.
+	periodTotals [values.PricePeriodCount]float64
+}
+
+// Returns how many times more likely the price in ``period`` is for the weeks with a
+// total prior chance of ``prior`` and total weighted likelihood of ``total``, than
+// for every other week. Returns false if there are no other weeks to compare to, or
+// the price is impossible for them.
+func (explainer *explainer) ratioAgainstOthers(
+	period PricePeriod, prior float64, total float64,
+) (float64, bool) {
+	otherPrior := explainer.priorTotal - prior
+	otherTotal := explainer.periodTotals[period] - total
+	// Guard against floating point drift when the weeks are nearly all there is.
+	if prior <= 0 || otherPrior <= explainer.priorTotal*1e-12 || otherTotal <= 0 {
+		return 0, false
+	}
+	return (total / prior) / (otherTotal / otherPrior), true
+}
+
+func newExplainer(prediction *Prediction, language ExplainLanguage) *explainer {
+	explainer := &explainer{
+		ticker:   &prediction.ticker,
+		language: language,
+		patterns: prediction.Patterns,
+	}
+
+	for period := PricePeriod(0); period < values.PricePeriodCount; period++ {
+		if _, _, known := explainer.ticker.observation(period); known {
+			explainer.known = append(explainer.known, period)
+		}
+	}
+
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			explainer.priorTotal += week.prior
+			for _, period := range explainer.known {
+				explainer.periodTotals[period] += week.prior *
+					week.PeriodLikelihoods[period]
+			}
+		}
+	}
+
+	return explainer
+}
+
+// Makes the evidence for the price in ``period``, without the phase information.
+func (explainer *explainer) evidence(period PricePeriod, ratio float64) *Evidence {
+	low, high, _ := explainer.ticker.observation(period)
+	evidence := &Evidence{
+		Period:          period,
+		Low:             low,
+		High:            high,
+		LikelihoodRatio: ratio,
+		language:        explainer.language,
+	}
+
+	price := explainer.ticker.Prices[period]
+	if price == 0 {
+		return evidence
+	}
+	for earlier := period - 1; earlier >= 0; earlier-- {
+		earlierPrice := explainer.ticker.Prices[earlier]
+		if earlierPrice == 0 {
+			continue
+		}
+		evidence.HasChange = true
+		evidence.ChangeFrom = earlier
+		evidence.Change = float64(price-earlierPrice) / float64(earlierPrice)
+		break
+	}
+	return evidence
+}
+
+// Returns the known period with the ratio furthest from 1, which is the price that
+// tells the pattern or week apart from the others the most, and that ratio. Returns
+// false if no period changes anything.
+func (explainer *explainer) keyPeriod(
+	ratio func(period PricePeriod) (float64, bool),
+) (key PricePeriod, keyRatio float64, found bool) {
+	keyStrength := 0.0
+	for _, period := range explainer.known {
+		periodRatio, ok := ratio(period)
+		if !ok {
+			continue
+		}
+
+		strength := math.Inf(1)
+		if periodRatio > 0 {
+			strength = math.Abs(math.Log(periodRatio))
+		}
+		if strength > keyStrength {
+			key, keyRatio, keyStrength, found = period, periodRatio, strength, true
+		}
+	}
+	return key, keyRatio, found
+}
+
+func (explainer *explainer) explainPattern(
+	pattern *PotentialPattern,
+) *PatternExplanation {
+	// Custom patterns go by the name they were defined with.
+	name := explainer.language.Pattern(pattern.Pattern)
+	if definition := pattern.definition; definition != nil &&
+		definition.Name != "" && definition.Name != pattern.Pattern.String() {
+		name = titleCase(definition.Name)
+	}
+
+	explanation := &PatternExplanation{
+		Pattern:  pattern.Pattern,
+		Name:     name,
+		Chance:   pattern.Chance(),
+		Reason:   ReasonPrior,
+		Weeks:    make([]*WeekExplanation, len(pattern.PotentialWeeks)),
+		language: explainer.language,
+	}
+
+	if len(pattern.PotentialWeeks) == 0 {
+		explainer.explainRuledOut(pattern, explanation)
+		return explanation
+	}
+
+	for i, week := range pattern.PotentialWeeks {
+		explanation.Weeks[i] = explainer.explainWeek(week)
+	}
+	sort.SliceStable(explanation.Weeks, func(i, j int) bool {
+		return explanation.Weeks[i].Chance > explanation.Weeks[j].Chance
+	})
+
+	patternPrior := 0.0
+	for _, week := range pattern.PotentialWeeks {
+		patternPrior += week.prior
+	}
+
+	key, ratio, found := explainer.keyPeriod(
+		func(period PricePeriod) (float64, bool) {
+			patternTotal := 0.0
+			for _, week := range pattern.PotentialWeeks {
+				patternTotal += week.prior * week.PeriodLikelihoods[period]
+			}
+			return explainer.ratioAgainstOthers(period, patternPrior, patternTotal)
+		},
+	)
+	if !found {
+		key, found = explainer.lastRuledOut(pattern)
+		ratio = math.Inf(1)
+	}
+	if !found {
+		return explanation
+	}
+
+	explanation.Reason = ReasonPrice
+	explanation.Key = explainer.evidence(key, ratio)
+	explainer.setPatternPhase(pattern, explanation.Key)
+	return explanation
+}
+
+// When every other pattern has been ruled out, there are no other patterns to compare
+// prices to. The price that ruled out the last of them is what tells the pattern
+// apart instead. Returns false if another pattern is still possible, or was not ruled
+// out by a price.
+func (explainer *explainer) lastRuledOut(
+	pattern *PotentialPattern,
+) (key PricePeriod, found bool) {
+	for _, other := range explainer.patterns {
+		if other == pattern {
+			continue
+		}
+		if len(other.PotentialWeeks) > 0 {
+			return 0, false
+		}
+		if len(other.ruledOutBy) == 0 {
+			continue
+		}
+
+		period := other.ruledOutBy[0].PricePeriod
+		if !found || period > key {
+			key, found = period, true
+		}
+	}
+	return key, found
+}
+
+// Sets the phase of the evidence to the one the pattern is most likely to be in for
+// the evidence's period.
+func (explainer *explainer) setPatternPhase(
+	pattern *PotentialPattern, evidence *Evidence,
+) {
+	period := evidence.Period
+	weights := make(map[string]float64)
+	var phase string
+	for _, week := range pattern.PotentialWeeks {
+		potential := week.Prices[period]
+		name := potential.PatternPhase.Name()
+
+		weights[name] += week.prior * week.PeriodLikelihoods[period]
+		if phase == "" || weights[name] > weights[phase] {
+			phase = name
+		}
+	}
+
+	evidence.Phases = []string{phase}
+	for _, week := range pattern.PotentialWeeks {
+		potential := week.Prices[period]
+		if potential.PatternPhase.Name() != phase {
+			continue
+		}
+		if evidence.PhaseMin == 0 || potential.MinPrice() < evidence.PhaseMin {
+			evidence.PhaseMin = potential.MinPrice()
+		}
+		if potential.MaxPrice() > evidence.PhaseMax {
+			evidence.PhaseMax = potential.MaxPrice()
+		}
+	}
+}
+
+// Explains a pattern that has no potential weeks.
+func (explainer *explainer) explainRuledOut(
+	pattern *PotentialPattern, explanation *PatternExplanation,
+) {
+	switch {
+	case len(pattern.ruledOutBy) > 0:
+		explanation.Reason = ReasonPrice
+		explanation.Key = explainer.evidence(pattern.ruledOutBy[0].PricePeriod, 0)
+
+		explanation.Key.Phases = make([]string, len(pattern.ruledOutBy))
+		for i, ruledOutBy := range pattern.ruledOutBy {
+			explanation.Key.Phases[i] = ruledOutBy.PatternPhase.Name()
+		}
+
+		// A range is only useful if there was a single phase to get it from.
+		if len(pattern.ruledOutBy) == 1 {
+			explanation.Key.PhaseMin = pattern.ruledOutBy[0].MinPrice()
+			explanation.Key.PhaseMax = pattern.ruledOutBy[0].MaxPrice()
+		}
+	case pattern.excludedWeeks > 0:
+		explanation.Reason = ReasonConstraints
+	default:
+		explanation.Reason = ReasonPreviousPattern
+	}
+}
+
+func (explainer *explainer) explainWeek(week *PotentialWeek) *WeekExplanation {
+	explanation := &WeekExplanation{
+		Week:     week,
+		Chance:   week.Chance(),
+		Reason:   ReasonPrior,
+		language: explainer.language,
+	}
+
+	var lastPhase PatternPhase
+	for _, potential := range week.Prices {
+		if potential.PatternPhase != lastPhase {
+			explanation.Phases = append(explanation.Phases, PhaseSpan{
+				Phase:    potential.PatternPhase.Name(),
+				Start:    potential.PricePeriod,
+				language: explainer.language,
+			})
+			lastPhase = potential.PatternPhase
+		}
+		explanation.Phases[len(explanation.Phases)-1].End = potential.PricePeriod
+	}
+
+	key, ratio, found := explainer.keyPeriod(
+		func(period PricePeriod) (float64, bool) {
+			return explainer.ratioAgainstOthers(
+				period, week.prior, week.prior*week.PeriodLikelihoods[period],
+			)
+		},
+	)
+	if !found {
+		return explanation
+	}
+
+	potential := week.Prices[key]
+	explanation.Reason = ReasonPrice
+	explanation.Key = explainer.evidence(key, ratio)
+	explanation.Key.againstWeeks = true
+	explanation.Key.Phases = []string{potential.PatternPhase.Name()}
+	explanation.Key.PhaseMin = potential.MinPrice()
+	explanation.Key.PhaseMax = potential.MaxPrice()
+	return explanation
+}
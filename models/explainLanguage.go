@@ -0,0 +1,189 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainMessage is a sentence or phrase explanations are written with. Each message is
+// a format string for fmt.Sprintf, and its arguments are listed below. Languages can
+// put the arguments in a different order with explicit indexes, like %[2]v.
+type ExplainMessage int
+
+const (
+	// A chance. Args: the chance as a percentage from 0-100.
+	ExplainChance ExplainMessage = iota
+	// A chance that rounds to 0% but is not impossible. No args.
+	ExplainChanceUnderOne
+
+	// A pattern with a key price. Args: the pattern name, the chance and the evidence.
+	ExplainPatternPrice
+	// A pattern that can't follow last week's pattern. Args: the pattern name and the
+	// chance.
+	ExplainPatternPreviousPattern
+	// A pattern the prediction's constraints ruled out. Args: the pattern name and the
+	// chance.
+	ExplainPatternConstraints
+	// A pattern no price changed the chance of. Args: the pattern name and the chance.
+	ExplainPatternPrior
+
+	// A week with no key price. Args: the chance and the list of phase spans.
+	ExplainWeek
+	// A week with a key price. Args: the chance, the list of phase spans and the
+	// evidence.
+	ExplainWeekPrice
+	// Joins the phase spans of a week. No args.
+	ExplainListSeparator
+	// A phase that lasts a single period. Args: the phase name and the period.
+	ExplainSpan
+	// A phase that lasts more than one period. Args: the phase name, the first period
+	// and the last period.
+	ExplainSpanRange
+
+	// A price that didn't change. Args: the period and the price.
+	ExplainStayed
+	// A price that rose. Args: the period, the percentage it rose by and the period it
+	// rose over.
+	ExplainRose
+	// A price that fell. Args: the period, the percentage it fell by and the period it
+	// fell from.
+	ExplainFell
+	// An exact price. Args: the period and the price.
+	ExplainWas
+	// A price with only a lower bound. Args: the period and the bound.
+	ExplainAtLeast
+	// A price with only an upper bound. Args: the period and the bound.
+	ExplainAtMost
+	// A price with both bounds. Args: the period, the lower bound and the upper bound.
+	ExplainBetween
+
+	// A single phase. Args: the phase name.
+	ExplainPhase
+	// More than one phase. Args: the phase names, joined with ExplainPhaseOr.
+	ExplainPhases
+	// Joins two phase names. Args: the names so far and the next name.
+	ExplainPhaseOr
+
+	// A price the phases can't make. Args: the price and the phases.
+	ExplainCantMake
+	// A price the phase can't make. Args: the price, the phase, and the lowest and
+	// highest price of the phase.
+	ExplainCantMakeRange
+	// A price that fits the phases. Args: the price and the phases.
+	ExplainFits
+	// A price less likely for the week than for the others. Args: the price and the
+	// phases.
+	ExplainLessLikelyWeeks
+	// A price less likely for the pattern than for the others. Args: the price and the
+	// phases.
+	ExplainLessLikelyPatterns
+)
+
+var englishExplainMessages = [...]string{
+	ExplainChance:         "%.0f%%",
+	ExplainChanceUnderOne: "under 1%",
+
+	ExplainPatternPrice: "%[1]v is %[2]v because %[3]v",
+	ExplainPatternPreviousPattern: "%[1]v is %[2]v because it can't follow last " +
+		"week's pattern",
+	ExplainPatternConstraints: "%[1]v is %[2]v because the prediction's " +
+		"constraints rule it out",
+	ExplainPatternPrior: "%[1]v is %[2]v based on last week's pattern",
+
+	ExplainWeek:          "%[1]v: %[2]v",
+	ExplainWeekPrice:     "%[1]v: %[2]v, because %[3]v",
+	ExplainListSeparator: ", ",
+	ExplainSpan:          "%[1]v (%[2]v)",
+	ExplainSpanRange:     "%[1]v (%[2]v to %[3]v)",
+
+	ExplainStayed:  "%[1]v stayed at %[2]v bells",
+	ExplainRose:    "%[1]v rose %.0[2]f%% over %[3]v",
+	ExplainFell:    "%[1]v fell %.0[2]f%% over %[3]v",
+	ExplainWas:     "%[1]v was %[2]v bells",
+	ExplainAtLeast: "%[1]v was at least %[2]v bells",
+	ExplainAtMost:  "%[1]v was at most %[2]v bells",
+	ExplainBetween: "%[1]v was between %[2]v and %[3]v bells",
+
+	ExplainPhase:   "the %v phase",
+	ExplainPhases:  "the %v phases",
+	ExplainPhaseOr: "%[1]v or %[2]v",
+
+	ExplainCantMake:      "%[1]v, which %[2]v can't make",
+	ExplainCantMakeRange: "%[1]v, which %[2]v can't make (%[3]v-%[4]v bells)",
+	ExplainFits:          "%[1]v, which fits %[2]v",
+	ExplainLessLikelyWeeks: "%[1]v, which is less likely in %[2]v than in " +
+		"other weeks",
+	ExplainLessLikelyPatterns: "%[1]v, which is less likely in %[2]v than in " +
+		"other patterns",
+}
+
+// English returns the English format string of the message, or "" if there is no such
+// message.
+func (message ExplainMessage) English() string {
+	if message < 0 || int(message) >= len(englishExplainMessages) {
+		return ""
+	}
+	return englishExplainMessages[message]
+}
+
+// ExplainLanguage writes explanations in a language. The catalogs of the locale
+// package are each an ExplainLanguage.
+type ExplainLanguage interface {
+	// The name of a pattern, like "Big Spike".
+	Pattern(pattern PricePattern) string
+	// The name of a phase, from the name it reports from PatternPhase.Name().
+	Phase(name string) string
+	// The name of a price period, like "Tuesday PM".
+	Period(period PricePeriod) string
+	// Formats a message with its arguments.
+	Message(message ExplainMessage, args ...interface{}) string
+}
+
+// EnglishExplain writes explanations in English. Prediction.Explain uses it.
+var EnglishExplain ExplainLanguage = englishExplain{}
+
+type englishExplain struct{}
+
+func (englishExplain) Pattern(pattern PricePattern) string {
+	return titleCase(pattern.String())
+}
+
+func (englishExplain) Phase(name string) string {
+	return name
+}
+
+// "Tuesday PM"
+func (englishExplain) Period(period PricePeriod) string {
+	return fmt.Sprintf("%v %v", period.Weekday(), period.ToD())
+}
+
+func (englishExplain) Message(message ExplainMessage, args ...interface{}) string {
+	return fmt.Sprintf(message.English(), args...)
+}
+
+// Returns EnglishExplain if ``language`` is nil, so explanations that were not made
+// by Explain can still be written.
+func explainLanguageOrEnglish(language ExplainLanguage) ExplainLanguage {
+	if language == nil {
+		return EnglishExplain
+	}
+	return language
+}
+
+// Formats a 0.0-1.0 chance as a whole percentage. Chances that round to 0 but are not
+// impossible are shown as "under 1%".
+func formatChance(language ExplainLanguage, chance float64) string {
+	if chance > 0 && chance < 0.005 {
+		return language.Message(ExplainChanceUnderOne)
+	}
+	return language.Message(ExplainChance, chance*100)
+}
+
+// "BIG SPIKE" -> "Big Spike"
+func titleCase(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
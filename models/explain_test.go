@@ -0,0 +1,223 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func explainTicker(
+	t *testing.T, ticker *PriceTicker, options PredictOptions,
+) *Explanation {
+	prediction, err := (&Predictor{Ticker: ticker, PredictOptions: options}).Predict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prediction.Explain()
+}
+
+// Returns the explanation for ``pattern``, failing the test if there isn't one.
+func findPatternExplanation(
+	t *testing.T, explanation *Explanation, pattern PricePattern,
+) *PatternExplanation {
+	for _, patternExplanation := range explanation.Patterns {
+		if patternExplanation.Pattern == pattern {
+			return patternExplanation
+		}
+	}
+	t.Fatalf("no explanation for %v", pattern)
+	return nil
+}
+
+func TestExplainPrices(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 3)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+	ticker.Prices[2] = 90
+	ticker.Prices[3] = 140
+
+	explanation := explainTicker(t, ticker, PredictOptions{})
+	if !assert.Len(explanation.Patterns, 4) {
+		t.FailNow()
+	}
+
+	// Patterns are sorted most likely first.
+	for i := 1; i < len(explanation.Patterns); i++ {
+		assert.GreaterOrEqual(
+			explanation.Patterns[i-1].Chance, explanation.Patterns[i].Chance,
+		)
+	}
+
+	// Prices never rise on a decreasing week, so the first rise rules it out.
+	decreasing := findPatternExplanation(t, explanation, DECREASING)
+	assert.Equal(0.0, decreasing.Chance)
+	assert.Equal(ReasonPrice, decreasing.Reason)
+	assert.Empty(decreasing.Weeks)
+	if assert.NotNil(decreasing.Key) {
+		assert.Equal(PricePeriod(2), decreasing.Key.Period)
+		assert.Equal(0.0, decreasing.Key.LikelihoodRatio)
+		assert.Equal([]string{"whomp whomp"}, decreasing.Key.Phases)
+		assert.True(decreasing.Key.HasChange)
+		assert.Equal(PricePeriod(1), decreasing.Key.ChangeFrom)
+		assert.Greater(decreasing.Key.PhaseMax, 0)
+	}
+	assert.Equal(
+		"Decreasing is 0% because Tuesday AM rose 10% over Monday PM, which the "+
+			"whomp whomp phase can't make (76-80 bells)",
+		decreasing.String(),
+	)
+
+	for _, pattern := range explanation.Patterns {
+		if pattern.Chance == 0 {
+			continue
+		}
+		assert.Equal(ReasonPrice, pattern.Reason, pattern.Name)
+		if !assert.NotNil(pattern.Key, pattern.Name) {
+			continue
+		}
+		assert.Len(pattern.Key.Phases, 1, pattern.Name)
+		assert.Contains(pattern.String(), pattern.Name+" is ")
+		assert.Contains(pattern.String(), " because ")
+
+		// Every week's phases should cover the whole week, in order.
+		for _, week := range pattern.Weeks {
+			if !assert.NotEmpty(week.Phases) {
+				continue
+			}
+			assert.Equal(PricePeriod(0), week.Phases[0].Start)
+			assert.Equal(PricePeriod(11), week.Phases[len(week.Phases)-1].End)
+			for i := 1; i < len(week.Phases); i++ {
+				assert.Equal(week.Phases[i-1].End+1, week.Phases[i].Start)
+			}
+		}
+	}
+}
+
+func TestExplainBigSpike(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 4)
+	for i, price := range []int{88, 84, 80, 125, 190} {
+		ticker.Prices[i] = price
+	}
+
+	explanation := explainTicker(t, ticker, PredictOptions{})
+
+	// Only a big spike can make 190 bells this early, so the price that ruled out the
+	// small spike is what tells the big spike apart.
+	bigSpike := findPatternExplanation(t, explanation, BIGSPIKE)
+	assert.Equal(ReasonPrice, bigSpike.Reason)
+	if assert.NotNil(bigSpike.Key) {
+		assert.Equal(PricePeriod(4), bigSpike.Key.Period)
+		assert.Equal([]string{"sharp increase"}, bigSpike.Key.Phases)
+	}
+	assert.Equal(
+		"Big Spike is 100% because Wednesday AM rose 52% over Tuesday PM, which fits "+
+			"the sharp increase phase",
+		bigSpike.String(),
+	)
+
+	smallSpike := findPatternExplanation(t, explanation, SMALLSPIKE)
+	assert.Equal(
+		"Small Spike is 0% because Wednesday AM rose 52% over Tuesday PM, which the "+
+			"small spike phase can't make (90-140 bells)",
+		smallSpike.String(),
+	)
+}
+
+// Two patterns are told apart by the same price, which favors one and not the other.
+func TestExplainAgainstOtherPatterns(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 3)
+	for i, price := range []int{86, 82, 90, 140} {
+		ticker.Prices[i] = price
+	}
+
+	explanation := explainTicker(t, ticker, PredictOptions{})
+
+	bigSpike := findPatternExplanation(t, explanation, BIGSPIKE)
+	smallSpike := findPatternExplanation(t, explanation, SMALLSPIKE)
+	if !assert.NotNil(bigSpike.Key) || !assert.NotNil(smallSpike.Key) {
+		t.FailNow()
+	}
+	assert.Greater(bigSpike.Key.LikelihoodRatio, 1.0)
+	assert.InDelta(
+		1/bigSpike.Key.LikelihoodRatio, smallSpike.Key.LikelihoodRatio, 0.000001,
+	)
+	assert.Equal(
+		"Small Spike is 38% because Monday AM was 86 bells, which is less likely in "+
+			"the steady decrease phase than in other patterns",
+		smallSpike.String(),
+	)
+}
+
+func TestExplainNoPrices(t *testing.T) {
+	assert := assert.New(t)
+
+	explanation := explainTicker(t, NewTicker(100, FLUCTUATING, 0), PredictOptions{})
+	for _, pattern := range explanation.Patterns {
+		assert.Equal(ReasonPrior, pattern.Reason, pattern.Name)
+		assert.Nil(pattern.Key, pattern.Name)
+		for _, week := range pattern.Weeks {
+			assert.Equal(ReasonPrior, week.Reason)
+			assert.Nil(week.Key)
+		}
+	}
+
+	bigSpike := findPatternExplanation(t, explanation, BIGSPIKE)
+	assert.Equal("Big Spike is 30% based on last week's pattern", bigSpike.String())
+}
+
+func TestExplainPriorsAndConstraints(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 0)
+	ticker.Prices[0] = 86
+
+	explanation := explainTicker(t, ticker, PredictOptions{
+		Priors:      map[PricePattern]float64{BIGSPIKE: 1, DECREASING: 1},
+		Constraints: []Constraint{ExcludePattern(DECREASING)},
+	})
+
+	fluctuating := findPatternExplanation(t, explanation, FLUCTUATING)
+	assert.Equal(ReasonPreviousPattern, fluctuating.Reason)
+	assert.Equal(
+		"Fluctuating is 0% because it can't follow last week's pattern",
+		fluctuating.String(),
+	)
+
+	decreasing := findPatternExplanation(t, explanation, DECREASING)
+	assert.Equal(ReasonConstraints, decreasing.Reason)
+
+	bigSpike := findPatternExplanation(t, explanation, BIGSPIKE)
+	assert.Equal(1.0, bigSpike.Chance)
+}
+
+func TestExplainPeriodLikelihoods(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 2)
+	ticker.Prices[0] = 86
+	ticker.Intervals[2] = PriceInterval{Min: 80, Max: 90}
+
+	prediction, err := (&Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			assert.Greater(week.PeriodLikelihoods[0], 0.0)
+			assert.Equal(0.0, week.PeriodLikelihoods[1])
+			assert.Greater(week.PeriodLikelihoods[2], 0.0)
+			for period := 3; period < len(week.PeriodLikelihoods); period++ {
+				assert.Equal(0.0, week.PeriodLikelihoods[period])
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+)
+
+// A representative ticker to benchmark and test predictions with.
+type benchmarkFixture struct {
+	Name   string
+	Ticker *PriceTicker
+}
+
+// Returns the fixtures, from the fewest prices to the most. The tickers are made fresh
+// on each call, so they can be changed.
+func benchmarkFixtures() []*benchmarkFixture {
+	return []*benchmarkFixture{
+		// No prices, so every week is possible.
+		{
+			Name:   "Empty",
+			Ticker: NewTicker(100, UNKNOWN, 0),
+		},
+		// No purchase price, so every week is worked out for 21 purchase prices.
+		{
+			Name: "UnknownPurchase",
+			Ticker: &PriceTicker{
+				PurchasePrice:   0,
+				PreviousPattern: FLUCTUATING,
+				Prices:          [12]int{86, 82},
+				CurrentPeriod:   1,
+			},
+		},
+		// Half a week of prices, which only fit the start of a spike.
+		{
+			Name: "PartialWeek",
+			Ticker: &PriceTicker{
+				PurchasePrice:   100,
+				PreviousPattern: DECREASING,
+				Prices:          [12]int{86, 82, 78, 74, 70, 126},
+				CurrentPeriod:   5,
+			},
+		},
+		// A full big spike week.
+		{
+			Name: "FullWeek",
+			Ticker: &PriceTicker{
+				PurchasePrice:   100,
+				PreviousPattern: SMALLSPIKE,
+				Prices:          [12]int{86, 82, 78, 74, 125, 180, 480, 190, 135, 73, 60, 48},
+				CurrentPeriod:   11,
+			},
+		},
+		// Prices at the very bottom of what the game can make, which only a single
+		// week fits. Most weeks are only ruled out late in the progression.
+		{
+			Name: "NearImpossible",
+			Ticker: &PriceTicker{
+				PurchasePrice:   90,
+				PreviousPattern: BIGSPIKE,
+				Prices:          [12]int{36, 34, 31, 29, 27, 24, 22},
+				CurrentPeriod:   6,
+			},
+		},
+	}
+}
+
+// Returns the ticker of the fixture called ``name``, failing the test if there is not
+// one.
+func fixtureTicker(tb testing.TB, name string) *PriceTicker {
+	for _, fixture := range benchmarkFixtures() {
+		if fixture.Name == name {
+			return fixture.Ticker
+		}
+	}
+	tb.Fatalf("no fixture named %v", name)
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"github.com/peake100/turnup-go/errs"
 	"strings"
 )
@@ -14,14 +15,36 @@ import (
 // 	4 = "UNKNOWN"
 type PricePattern int
 
+var patternNames = [5]string{
+	"FLUCTUATING",
+	"BIG SPIKE",
+	"DECREASING",
+	"SMALL SPIKE",
+	"UNKNOWN",
+}
+
+// String returns the name of the pattern. Values outside of 0-4 are written as
+// "PricePattern(n)" rather than panicking.
 func (pattern PricePattern) String() string {
-	return [5]string{
-		"FLUCTUATING",
-		"BIG SPIKE",
-		"DECREASING",
-		"SMALL SPIKE",
-		"UNKNOWN",
-	}[pattern]
+	name, err := pattern.Name()
+	if err != nil {
+		return fmt.Sprintf("PricePattern(%d)", int(pattern))
+	}
+	return name
+}
+
+// Name returns the name of the pattern, or errs.ErrBadPatternIndex if the pattern is
+// not 0-4.
+func (pattern PricePattern) Name() (string, error) {
+	if !pattern.valid() {
+		return "", errs.ErrBadPatternIndex
+	}
+	return patternNames[pattern], nil
+}
+
+// Whether the pattern is one of the 5 values above.
+func (pattern PricePattern) valid() bool {
+	return pattern >= FLUCTUATING && pattern <= UNKNOWN
 }
 
 // Because we are including unknown, we need to allow for the 0 position of every
@@ -78,37 +101,83 @@ var initialChanceMatrix = [5][4]float64{
 	{0.35, 0.2625, 0.1375, 0.25},
 }
 
-// Returns a the chance of this pattern occurring based on the pattern from last week
+// Returns a the chance of this pattern occurring based on the pattern from last week.
+// Panics if either pattern is invalid.
+//
+// Deprecated: use ChanceAfter, which returns an error instead of panicking.
 func (pattern PricePattern) BaseChance(previous PricePattern) float64 {
+	chance, err := pattern.ChanceAfter(previous)
+	if err != nil {
+		panic(err)
+	}
+	return chance
+}
+
+// Returns a the chance of this pattern occurring based on the pattern from last week.
+// Returns errs.ErrUnknownBaseChanceInvalid if this pattern is UNKNOWN, and
+// errs.ErrBadPatternIndex if either pattern is not 0-4.
+func (pattern PricePattern) ChanceAfter(previous PricePattern) (float64, error) {
+	if !pattern.valid() || !previous.valid() {
+		return 0, errs.ErrBadPatternIndex
+	}
 	if pattern == UNKNOWN {
-		panic(errs.ErrUnknownBaseChanceInvalid)
+		return 0, errs.ErrUnknownBaseChanceInvalid
 	}
 	// Do the lookup
-	return initialChanceMatrix[previous][pattern]
+	return initialChanceMatrix[previous][pattern], nil
 }
 
 // Returns a new set of phase definitions that can be used to calculate the possible
-// price values for a week.
+// price values for a week. Panics if the pattern is not an in-game pattern.
+//
+// Deprecated: use Progression, which returns an error instead of panicking.
 func (pattern PricePattern) PhaseProgression(ticker *PriceTicker) []PatternPhase {
+	phases, err := pattern.Progression(ticker)
+	if err != nil {
+		panic(err)
+	}
+	return phases
+}
+
+// Returns a new set of phase definitions that can be used to calculate the possible
+// price values for a week. Returns errs.ErrUnknownPhasesInvalid for UNKNOWN, and
+// errs.ErrBadPatternIndex if the pattern is not 0-4.
+func (pattern PricePattern) Progression(ticker *PriceTicker) ([]PatternPhase, error) {
+	progression, err := pattern.progressionFunc()
+	if err != nil {
+		return nil, err
+	}
+	return progression(ticker), nil
+}
+
+// Returns the function that makes the phases of this pattern, with the same errors as
+// Progression.
+func (pattern PricePattern) progressionFunc() (
+	func(ticker *PriceTicker) []PatternPhase, error,
+) {
 	switch {
 	case pattern == FLUCTUATING:
-		return fluctuatingProgression(ticker)
+		return fluctuatingProgression, nil
 	case pattern == BIGSPIKE:
-		return bigSpikeProgression(ticker)
+		return bigSpikeProgression, nil
 	case pattern == DECREASING:
-		return decreasingProgression(ticker)
+		return decreasingProgression, nil
 	case pattern == SMALLSPIKE:
-		return smallSpikeProgression(ticker)
+		return smallSpikeProgression, nil
 	case pattern == UNKNOWN:
-		panic(errs.ErrUnknownPhasesInvalid)
+		return nil, errs.ErrUnknownPhasesInvalid
 	default:
-		panic(errs.ErrBadPatternIndex)
+		return nil, errs.ErrBadPatternIndex
 	}
 }
 
 // The total possible phase combinations for this pattern. Can be used to determine
 // actual chance of this pattern once possibilities have been removed by a ticker.
+// Returns 0 for UNKNOWN and invalid patterns.
 func (pattern PricePattern) PermutationCount() int {
+	if !pattern.valid() || pattern == UNKNOWN {
+		return 0
+	}
 	return [4]int{56, 7, 1, 8}[pattern]
 }
 
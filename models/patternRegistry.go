@@ -0,0 +1,189 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"golang.org/x/xerrors"
+)
+
+// PatternDefinition describes a price pattern the predictor can consider. Patterns can
+// either be built from phase definitions, or from a Go function that returns the
+// phase progression directly.
+type PatternDefinition struct {
+	// The value this pattern is identified by in tickers and predictions.
+	Pattern PricePattern
+	Name    string
+
+	// The chance of this pattern occurring, keyed by last week's pattern. Include the
+	// UNKNOWN key for when last week's pattern is not known. Missing keys are treated
+	// as a 0% chance.
+	Chances map[PricePattern]float64
+
+	// The phases of this pattern, in order. Ignored if Progression is set.
+	Phases []*PhaseDefinition
+
+	// Returns a new phase progression for a ticker. Used for patterns whose phases
+	// are implemented in Go.
+	Progression func(ticker *PriceTicker) []PatternPhase
+
+	// The total possible phase combinations for this pattern, counted on
+	// registration.
+	permutationCount int
+}
+
+// Returns the chance of this pattern occurring based on the pattern from last week.
+func (definition *PatternDefinition) BaseChance(previous PricePattern) float64 {
+	return definition.Chances[previous]
+}
+
+// Returns a new set of phases that can be used to calculate the possible price values
+// for a week.
+func (definition *PatternDefinition) PhaseProgression(
+	ticker *PriceTicker,
+) []PatternPhase {
+	if definition.Progression != nil {
+		return definition.Progression(ticker)
+	}
+
+	phases := make([]PatternPhase, len(definition.Phases))
+	for i, phaseDefinition := range definition.Phases {
+		phase := &patternPhaseAuto{
+			phaseImplement: &definedPhase{definition: phaseDefinition},
+		}
+		phase.SetTicker(ticker)
+		phases[i] = phase
+	}
+	return phases
+}
+
+// The total possible phase combinations for this pattern. Only set once the definition
+// has been registered.
+func (definition *PatternDefinition) PermutationCount() int {
+	return definition.permutationCount
+}
+
+func (definition *PatternDefinition) validate() error {
+	if definition.Pattern == UNKNOWN {
+		return xerrors.Errorf(
+			"UNKNOWN cannot be defined: %w", errs.ErrInvalidPatternDefinition,
+		)
+	}
+	if definition.Progression == nil && len(definition.Phases) == 0 {
+		return xerrors.Errorf(
+			"pattern '%v' has no phases: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+	if definition.Progression == nil {
+		for i, phase := range definition.Phases {
+			if err := phase.validate(i, len(definition.Phases)); err != nil {
+				return xerrors.Errorf("pattern '%v': %w", definition.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Counts the possible phase combinations of this pattern that make up a full week.
+func (definition *PatternDefinition) countPermutations() int {
+	count := 0
+	brancher := &phaseBrancher{
+		onComplete: func([]PatternPhase) { count++ },
+	}
+	brancher.branchPhases(definition.PhaseProgression(NewTicker(0, UNKNOWN, 0)))
+	return count
+}
+
+// PatternRegistry holds the patterns a predictor considers. Registering a new
+// definition lets the predictor keep up with game updates or variants without
+// changes to the predictor itself.
+type PatternRegistry struct {
+	definitions []*PatternDefinition
+}
+
+// Registers a pattern definition. Returns an error if the definition is invalid, can
+// never make a full week, or its pattern is already registered.
+func (registry *PatternRegistry) Register(definition *PatternDefinition) error {
+	if err := definition.validate(); err != nil {
+		return err
+	}
+	if _, err := registry.Get(definition.Pattern); err == nil {
+		return xerrors.Errorf(
+			"pattern %v: %w", int(definition.Pattern), errs.ErrPatternAlreadyRegistered,
+		)
+	}
+
+	definition.permutationCount = definition.countPermutations()
+	if definition.permutationCount == 0 {
+		return xerrors.Errorf(
+			"pattern '%v' has no phase combinations that make a full week: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+
+	registry.definitions = append(registry.definitions, definition)
+	return nil
+}
+
+// Returns the definition for a pattern.
+func (registry *PatternRegistry) Get(
+	pattern PricePattern,
+) (*PatternDefinition, error) {
+	for _, definition := range registry.definitions {
+		if definition.Pattern == pattern {
+			return definition, nil
+		}
+	}
+	return nil, xerrors.Errorf(
+		"pattern %v: %w", int(pattern), errs.ErrPatternNotRegistered,
+	)
+}
+
+// Returns the registered definitions in the order they were registered.
+func (registry *PatternRegistry) Definitions() []*PatternDefinition {
+	definitions := make([]*PatternDefinition, len(registry.definitions))
+	copy(definitions, registry.definitions)
+	return definitions
+}
+
+// Returns an empty pattern registry.
+func NewPatternRegistry() *PatternRegistry {
+	return new(PatternRegistry)
+}
+
+// Returns a new registry with the four in-game patterns registered. More patterns can
+// be registered on the result without affecting other registries.
+func DefaultPatternRegistry() *PatternRegistry {
+	registry := NewPatternRegistry()
+	for _, pattern := range PATTERNSGAME {
+		// The in-game patterns are always valid, so none of these errors can happen.
+		chances := make(map[PricePattern]float64, len(PATTERNS))
+		for _, previous := range PATTERNS {
+			chance, err := pattern.ChanceAfter(previous)
+			if err != nil {
+				panic(err)
+			}
+			chances[previous] = chance
+		}
+
+		progression, err := pattern.progressionFunc()
+		if err != nil {
+			panic(err)
+		}
+
+		err = registry.Register(&PatternDefinition{
+			Pattern:     pattern,
+			Name:        pattern.String(),
+			Chances:     chances,
+			Progression: progression,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// The registry used by predictors that do not have one set.
+var defaultPatterns = DefaultPatternRegistry()
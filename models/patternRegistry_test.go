@@ -0,0 +1,192 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+// The decreasing pattern, described with phase definitions.
+func decreasingDefinition() *PatternDefinition {
+	return &PatternDefinition{
+		Pattern: DECREASING,
+		Name:    "DECREASING",
+		Chances: map[PricePattern]float64{
+			FLUCTUATING: 0.15,
+			BIGSPIKE:    0.20,
+			DECREASING:  0.05,
+			SMALLSPIKE:  0.15,
+			UNKNOWN:     0.1375,
+		},
+		Phases: []*PhaseDefinition{
+			{
+				Name:        "whomp whomp",
+				Multipliers: []MultiplierRange{{Min: 0.85, Max: 0.9}},
+				Compounding: &Compounding{Min: []float32{-0.05}, Max: []float32{-0.03}},
+				Length:      LengthChoices{12},
+			},
+		},
+	}
+}
+
+// A made-up pattern that holds flat all week.
+func flatDefinition() *PatternDefinition {
+	return &PatternDefinition{
+		Pattern: PricePattern(5),
+		Name:    "FLAT",
+		Chances: map[PricePattern]float64{UNKNOWN: 0.2},
+		Phases: []*PhaseDefinition{
+			{
+				Name:        "flat",
+				Multipliers: []MultiplierRange{{Min: 0.95, Max: 1.05}},
+				Length:      LengthRange{Min: LengthExpr{Base: 12}, Max: LengthExpr{Base: 12}},
+			},
+		},
+	}
+}
+
+func TestDefaultRegistryPermutations(t *testing.T) {
+	registry := DefaultPatternRegistry()
+
+	for _, pattern := range PATTERNSGAME {
+		t.Run(pattern.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			definition, err := registry.Get(pattern)
+			if !assert.NoError(err) {
+				t.FailNow()
+			}
+			assert.Equal(pattern.PermutationCount(), definition.PermutationCount())
+			chance, err := pattern.ChanceAfter(UNKNOWN)
+			assert.NoError(err)
+			assert.Equal(chance, definition.BaseChance(UNKNOWN))
+		})
+	}
+}
+
+func TestRegistryDefinedPhasesMatchBuiltin(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := NewPatternRegistry()
+	if !assert.NoError(registry.Register(decreasingDefinition())) {
+		t.FailNow()
+	}
+
+	ticker := NewTicker(100, UNKNOWN, 3)
+	ticker.Prices[0] = 87
+	ticker.Prices[1] = 83
+	ticker.Prices[2] = 79
+
+	defined, err := (&Predictor{
+		Ticker: ticker, PredictOptions: PredictOptions{Patterns: registry},
+	}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	builtin, err := (&Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	definedWeek := defined.Patterns[0].PotentialWeeks[0]
+	builtinWeek := builtin.Patterns[DECREASING].PotentialWeeks[0]
+	for i, period := range builtinWeek.Prices {
+		assert.Equal(period.MinPrice(), definedWeek.Prices[i].MinPrice())
+		assert.Equal(period.MaxPrice(), definedWeek.Prices[i].MaxPrice())
+	}
+	assert.Equal(1.0, defined.Patterns[0].Chance())
+}
+
+func TestRegistryCustomPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := DefaultPatternRegistry()
+	if !assert.NoError(registry.Register(flatDefinition())) {
+		t.FailNow()
+	}
+
+	ticker := NewTicker(100, UNKNOWN, 1)
+	ticker.Prices[0] = 100
+
+	prediction, err := (&Predictor{
+		Ticker: ticker, PredictOptions: PredictOptions{Patterns: registry},
+	}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	if !assert.Len(prediction.Patterns, 5) {
+		t.FailNow()
+	}
+	flat := prediction.Patterns[4]
+	assert.Equal(PricePattern(5), flat.Pattern)
+	assert.Len(flat.PotentialWeeks, 1)
+	assert.Greater(flat.Chance(), 0.0)
+	assert.Equal(95, flat.MinPrice())
+	assert.Equal(105, flat.MaxPrice())
+
+	// A price of 100 can't happen on a decreasing week, so the pattern is out.
+	assert.Equal(0.0, prediction.Patterns[DECREASING].Chance())
+
+	total := 0.0
+	for _, pattern := range prediction.Patterns {
+		total += pattern.Chance()
+	}
+	assert.InDelta(1.0, total, 0.0005)
+}
+
+func TestRegistryRegisterErrors(t *testing.T) {
+	noPhases := flatDefinition()
+	noPhases.Phases = nil
+
+	noMultipliers := flatDefinition()
+	noMultipliers.Phases[0].Multipliers = nil
+
+	badPeak := flatDefinition()
+	badPeak.Phases[0].Spike = &SpikeMarker{Start: 1, End: 2, Peak: 3}
+
+	shortWeek := flatDefinition()
+	shortWeek.Phases[0].Length = LengthChoices{11}
+
+	unknown := flatDefinition()
+	unknown.Pattern = UNKNOWN
+
+	testCases := []struct {
+		Name       string
+		Definition *PatternDefinition
+		Err        error
+	}{
+		{"no phases", noPhases, errs.ErrInvalidPatternDefinition},
+		{"no multipliers", noMultipliers, errs.ErrInvalidPatternDefinition},
+		{"bad peak", badPeak, errs.ErrInvalidPatternDefinition},
+		{"short week", shortWeek, errs.ErrInvalidPatternDefinition},
+		{"unknown", unknown, errs.ErrInvalidPatternDefinition},
+		{"duplicate", decreasingDefinition(), errs.ErrPatternAlreadyRegistered},
+	}
+
+	for _, thisCase := range testCases {
+		t.Run(thisCase.Name, func(t *testing.T) {
+			err := DefaultPatternRegistry().Register(thisCase.Definition)
+			assert.True(t, xerrors.Is(err, thisCase.Err), "error is %v", thisCase.Err)
+		})
+	}
+}
+
+func TestRegistryNoPatternChance(t *testing.T) {
+	assert := assert.New(t)
+
+	registry := NewPatternRegistry()
+	if !assert.NoError(registry.Register(flatDefinition())) {
+		t.FailNow()
+	}
+
+	_, err := (&Predictor{
+		Ticker:         NewTicker(100, FLUCTUATING, 0),
+		PredictOptions: PredictOptions{Patterns: registry},
+	}).Predict()
+	assert.True(xerrors.Is(err, errs.ErrNoPatternChance))
+}
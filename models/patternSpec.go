@@ -0,0 +1,301 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/peake100/turnup-go/errs"
+	"golang.org/x/xerrors"
+	"io"
+)
+
+// PatternSpec is the data file description of a pattern. Specs are stored as a JSON
+// list, and compiled into a PatternDefinition to be registered with a
+// PatternRegistry.
+type PatternSpec struct {
+	// The value this pattern is identified by in tickers and predictions.
+	Pattern int    `json:"pattern"`
+	Name    string `json:"name"`
+
+	// The chance of this pattern occurring, keyed by the int value of last week's
+	// pattern. The UNKNOWN key (4) is used when last week's pattern is not known.
+	Chances map[int]float64 `json:"chances"`
+
+	Phases []*PhaseSpec `json:"phases"`
+}
+
+// PhaseSpec is the data file description of a phase. See PhaseDefinition for a
+// description of each field.
+type PhaseSpec struct {
+	Name             string            `json:"name"`
+	Multipliers      []MultiplierRange `json:"multipliers"`
+	Compounding      *Compounding      `json:"compounding,omitempty"`
+	Length           *LengthSpec       `json:"length"`
+	FinalAdjustments map[int]int       `json:"finalAdjustments,omitempty"`
+	Spike            *SpikeMarker      `json:"spike,omitempty"`
+}
+
+// LengthSpec is the data file description of a phase's possible lengths. Either
+// Choices, or both Min and Max, should be set. A length that is worked out from the
+// lengths of other phases has the same Min and Max, such as the random decrease phase
+// of the big spike pattern:
+//
+//	{"min": {"base": 7, "minus": [0]}, "max": {"base": 7, "minus": [0]}}
+type LengthSpec struct {
+	Choices []int       `json:"choices,omitempty"`
+	Min     *LengthExpr `json:"min,omitempty"`
+	Max     *LengthExpr `json:"max,omitempty"`
+}
+
+// Compiles the length spec into a length rule.
+func (spec *LengthSpec) rule() (LengthRule, error) {
+	switch {
+	case spec == nil:
+		return nil, xerrors.Errorf(
+			"length is missing: %w", errs.ErrInvalidPatternDefinition,
+		)
+	case len(spec.Choices) > 0 && spec.Min == nil && spec.Max == nil:
+		return LengthChoices(spec.Choices), nil
+	case len(spec.Choices) == 0 && spec.Min != nil && spec.Max != nil:
+		return LengthRange{Min: *spec.Min, Max: *spec.Max}, nil
+	default:
+		return nil, xerrors.Errorf(
+			"length must have either choices or a min and max: %w",
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+}
+
+// Compiles the spec into a pattern definition that can be registered.
+func (spec *PatternSpec) Definition() (*PatternDefinition, error) {
+	definition := &PatternDefinition{
+		Pattern: PricePattern(spec.Pattern),
+		Name:    spec.Name,
+		Chances: make(map[PricePattern]float64, len(spec.Chances)),
+		Phases:  make([]*PhaseDefinition, len(spec.Phases)),
+	}
+
+	for previous, chance := range spec.Chances {
+		definition.Chances[PricePattern(previous)] = chance
+	}
+
+	for i, phaseSpec := range spec.Phases {
+		lengthRule, err := phaseSpec.Length.rule()
+		if err != nil {
+			return nil, xerrors.Errorf(
+				"pattern '%v' phase '%v': %w", spec.Name, phaseSpec.Name, err,
+			)
+		}
+
+		definition.Phases[i] = &PhaseDefinition{
+			Name:             phaseSpec.Name,
+			Multipliers:      phaseSpec.Multipliers,
+			Compounding:      phaseSpec.Compounding,
+			Length:           lengthRule,
+			FinalAdjustments: phaseSpec.FinalAdjustments,
+			Spike:            phaseSpec.Spike,
+		}
+	}
+
+	return definition, nil
+}
+
+// Reads a JSON list of pattern specs.
+func ReadPatternSpecs(reader io.Reader) ([]*PatternSpec, error) {
+	var specs []*PatternSpec
+	if err := json.NewDecoder(reader).Decode(&specs); err != nil {
+		return nil, xerrors.Errorf("error decoding pattern specs: %w", err)
+	}
+	return specs, nil
+}
+
+// Writes pattern specs as an indented JSON list.
+func WritePatternSpecs(writer io.Writer, specs []*PatternSpec) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(specs); err != nil {
+		return xerrors.Errorf("error encoding pattern specs: %w", err)
+	}
+	return nil
+}
+
+// Compiles and registers pattern specs with a new registry.
+func NewPatternRegistryFromSpecs(specs []*PatternSpec) (*PatternRegistry, error) {
+	registry := NewPatternRegistry()
+	for _, spec := range specs {
+		definition, err := spec.Definition()
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.Register(definition); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Reads a JSON list of pattern specs and registers them with a new registry.
+func LoadPatternRegistry(reader io.Reader) (*PatternRegistry, error) {
+	specs, err := ReadPatternSpecs(reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewPatternRegistryFromSpecs(specs)
+}
+
+// Returns the chances for a built-in pattern keyed by int, for use in a spec.
+func builtinSpecChances(pattern PricePattern) map[int]float64 {
+	chances := make(map[int]float64, len(PATTERNS))
+	for _, previous := range PATTERNS {
+		// Only called for the in-game patterns, so this can't fail.
+		chance, err := pattern.ChanceAfter(previous)
+		if err != nil {
+			panic(err)
+		}
+		chances[int(previous)] = chance
+	}
+	return chances
+}
+
+// Returns a length spec for a single length worked out from other phases.
+func lengthSpecExpr(base int, minus ...int) *LengthSpec {
+	expr := LengthExpr{Base: base, Minus: minus}
+	return &LengthSpec{Min: &expr, Max: &expr}
+}
+
+// Returns the four in-game patterns described as specs. Registering the compiled
+// specs gives identical predictions to the Go implementations of each pattern, and
+// the specs can be written out as a starting point for describing new patterns.
+func BuiltinPatternSpecs() []*PatternSpec {
+	fluctuatingIncrease := []MultiplierRange{{Min: 0.9, Max: 1.4}}
+	fluctuatingDecrease := []MultiplierRange{{Min: 0.6, Max: 0.8}}
+	fluctuatingCompounding := &Compounding{
+		Min: []float32{-0.1}, Max: []float32{-0.04},
+	}
+
+	steadyDecreaseCompounding := &Compounding{
+		Min: []float32{-0.05}, Max: []float32{-0.03},
+	}
+
+	// The game subtracts both 0.02 and 0.03 discreetly here.
+	smallDecreaseCompounding := &Compounding{
+		Min: []float32{-0.02, -0.03}, Max: []float32{-0.03},
+	}
+
+	return []*PatternSpec{
+		{
+			Pattern: int(FLUCTUATING),
+			Name:    FLUCTUATING.String(),
+			Chances: builtinSpecChances(FLUCTUATING),
+			Phases: []*PhaseSpec{
+				{
+					Name:        PhaseMildIncrease,
+					Multipliers: fluctuatingIncrease,
+					Length:      &LengthSpec{Choices: []int{0, 1, 2, 3, 4, 5, 6}},
+				},
+				{
+					Name:        PhaseMildDecrease,
+					Multipliers: fluctuatingDecrease,
+					Compounding: fluctuatingCompounding,
+					Length:      &LengthSpec{Choices: []int{2, 3}},
+				},
+				{
+					Name:        PhaseMildIncrease,
+					Multipliers: fluctuatingIncrease,
+					Length:      lengthSpecExpr(7, 0, 4),
+				},
+				{
+					Name:        PhaseMildDecrease,
+					Multipliers: fluctuatingDecrease,
+					Compounding: fluctuatingCompounding,
+					Length:      lengthSpecExpr(5, 1),
+				},
+				{
+					Name:        PhaseMildIncrease,
+					Multipliers: fluctuatingIncrease,
+					Length: &LengthSpec{
+						Min: &LengthExpr{Base: 0},
+						Max: &LengthExpr{Base: 6, Minus: []int{0}},
+					},
+				},
+			},
+		},
+		{
+			Pattern: int(BIGSPIKE),
+			Name:    BIGSPIKE.String(),
+			Chances: builtinSpecChances(BIGSPIKE),
+			Phases: []*PhaseSpec{
+				{
+					Name:        PhaseSteadyDecrease,
+					Multipliers: []MultiplierRange{{Min: 0.85, Max: 0.9}},
+					Compounding: steadyDecreaseCompounding,
+					Length:      &LengthSpec{Choices: []int{1, 2, 3, 4, 5, 6, 7}},
+				},
+				{
+					Name: PhaseSharpIncrease,
+					Multipliers: []MultiplierRange{
+						{Min: 0.9, Max: 1.4},
+						{Min: 1.4, Max: 2},
+						{Min: 2, Max: 6},
+					},
+					Length: &LengthSpec{Choices: []int{3}},
+					Spike:  &SpikeMarker{Start: 2, End: 2, Big: true, Peak: 2},
+				},
+				{
+					Name: PhaseSharpDecrease,
+					Multipliers: []MultiplierRange{
+						{Min: 1.4, Max: 2},
+						{Min: 0.9, Max: 1.4},
+					},
+					Length: &LengthSpec{Choices: []int{2}},
+				},
+				{
+					Name:        PhaseRandomLow,
+					Multipliers: []MultiplierRange{{Min: 0.4, Max: 0.9}},
+					Length:      lengthSpecExpr(12-5, 0),
+				},
+			},
+		},
+		{
+			Pattern: int(DECREASING),
+			Name:    DECREASING.String(),
+			Chances: builtinSpecChances(DECREASING),
+			Phases: []*PhaseSpec{
+				{
+					Name:        PhaseConstantDecrease,
+					Multipliers: []MultiplierRange{{Min: 0.85, Max: 0.9}},
+					Compounding: steadyDecreaseCompounding,
+					Length:      &LengthSpec{Choices: []int{12}},
+				},
+			},
+		},
+		{
+			Pattern: int(SMALLSPIKE),
+			Name:    SMALLSPIKE.String(),
+			Chances: builtinSpecChances(SMALLSPIKE),
+			Phases: []*PhaseSpec{
+				{
+					Name:        PhaseSteadyDecrease,
+					Multipliers: []MultiplierRange{{Min: 0.4, Max: 0.9}},
+					Compounding: smallDecreaseCompounding,
+					Length:      &LengthSpec{Choices: []int{0, 1, 2, 3, 4, 5, 6, 7}},
+				},
+				{
+					Name: PhaseSmallSpike,
+					Multipliers: []MultiplierRange{
+						{Min: 0.9, Max: 1.4},
+						{Min: 0.9, Max: 1.4},
+						{Min: 1.4, Max: 2.0},
+					},
+					Length:           &LengthSpec{Choices: []int{5}},
+					FinalAdjustments: map[int]int{2: -1, 4: -1},
+					Spike:            &SpikeMarker{Start: 2, End: 4, Peak: 3},
+				},
+				{
+					Name:        PhaseSteadyDecrease,
+					Multipliers: []MultiplierRange{{Min: 0.4, Max: 0.9}},
+					Compounding: smallDecreaseCompounding,
+					Length:      lengthSpecExpr(7, 0),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"bytes"
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"strings"
+	"testing"
+)
+
+// Writes the built-in specs out and loads them back in, so the test covers the data
+// file round trip as well as the compiled phases.
+func loadBuiltinSpecRegistry(t *testing.T) *PatternRegistry {
+	buffer := new(bytes.Buffer)
+	if err := WritePatternSpecs(buffer, BuiltinPatternSpecs()); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := LoadPatternRegistry(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+func specTestTickers() map[string]*PriceTicker {
+	tickers := make(map[string]*PriceTicker)
+
+	tickers["no prices"] = NewTicker(100, UNKNOWN, 0)
+	tickers["unknown purchase"] = NewTicker(0, FLUCTUATING, 0)
+
+	bigSpike := NewTicker(100, DECREASING, 5)
+	for i, price := range []int{86, 82, 78, 120, 180, 550} {
+		bigSpike.Prices[i] = price
+	}
+	tickers["big spike"] = bigSpike
+
+	smallSpike := NewTicker(100, SMALLSPIKE, 6)
+	for i, price := range []int{85, 81, 77, 110, 130, 160, 161} {
+		smallSpike.Prices[i] = price
+	}
+	tickers["small spike"] = smallSpike
+
+	fluctuating := NewTicker(103, BIGSPIKE, 3)
+	for i, price := range []int{110, 120, 72, 66} {
+		fluctuating.Prices[i] = price
+	}
+	tickers["fluctuating"] = fluctuating
+
+	return tickers
+}
+
+func TestBuiltinSpecPermutations(t *testing.T) {
+	registry := loadBuiltinSpecRegistry(t)
+
+	for _, pattern := range PATTERNSGAME {
+		definition, err := registry.Get(pattern)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.Equal(t, pattern.PermutationCount(), definition.PermutationCount())
+	}
+}
+
+func TestBuiltinSpecsMatchGo(t *testing.T) {
+	registry := loadBuiltinSpecRegistry(t)
+
+	for name, ticker := range specTestTickers() {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			expected, err := (&Predictor{Ticker: ticker}).Predict()
+			if !assert.NoError(err, "go prediction") {
+				t.FailNow()
+			}
+			actual, err := (&Predictor{
+				Ticker: ticker, PredictOptions: PredictOptions{Patterns: registry},
+			}).Predict()
+			if !assert.NoError(err, "spec prediction") {
+				t.FailNow()
+			}
+
+			assert.Equal(expected.Heat, actual.Heat, "heat")
+			assert.Equal(expected.MinPrice(), actual.MinPrice(), "min price")
+			assert.Equal(expected.MaxPrice(), actual.MaxPrice(), "max price")
+			assert.Equal(
+				*expected.Spikes.Any().Breakdown(),
+				*actual.Spikes.Any().Breakdown(),
+				"spike breakdown",
+			)
+
+			for i, expectedPattern := range expected.Patterns {
+				actualPattern := actual.Patterns[i]
+				assert.Equal(expectedPattern.Pattern, actualPattern.Pattern)
+				assert.Equal(expectedPattern.Chance(), actualPattern.Chance())
+				if !assert.Len(
+					actualPattern.PotentialWeeks, len(expectedPattern.PotentialWeeks),
+				) {
+					continue
+				}
+
+				for j, expectedWeek := range expectedPattern.PotentialWeeks {
+					actualWeek := actualPattern.PotentialWeeks[j]
+					assert.Equal(expectedWeek.Chance(), actualWeek.Chance())
+					for k, expectedPeriod := range expectedWeek.Prices {
+						actualPeriod := actualWeek.Prices[k]
+						assert.Equal(expectedPeriod.PatternPhase.Name(),
+							actualPeriod.PatternPhase.Name())
+						assert.Equal(expectedPeriod.MinPrice(), actualPeriod.MinPrice())
+						assert.Equal(expectedPeriod.MaxPrice(), actualPeriod.MaxPrice())
+						assert.Equal(expectedPeriod.Spikes, actualPeriod.Spikes)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPatternSpecErrors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		JSON string
+	}{
+		{
+			"no length",
+			`[{"pattern": 5, "name": "A", "chances": {"4": 1},
+				"phases": [{"name": "a", "multipliers": [{"min": 1, "max": 1}]}]}]`,
+		},
+		{
+			"choices and range",
+			`[{"pattern": 5, "name": "A", "chances": {"4": 1},
+				"phases": [{"name": "a", "multipliers": [{"min": 1, "max": 1}],
+				"length": {"choices": [12], "min": {"base": 12}, "max": {"base": 12}}}]}]`,
+		},
+		{
+			"bad reference",
+			`[{"pattern": 5, "name": "A", "chances": {"4": 1},
+				"phases": [{"name": "a", "multipliers": [{"min": 1, "max": 1}],
+				"length": {"min": {"base": 12, "minus": [3]},
+				"max": {"base": 12, "minus": [3]}}}]}]`,
+		},
+	}
+
+	for _, thisCase := range testCases {
+		t.Run(thisCase.Name, func(t *testing.T) {
+			_, err := LoadPatternRegistry(strings.NewReader(thisCase.JSON))
+			assert.True(t, xerrors.Is(err, errs.ErrInvalidPatternDefinition), err)
+		})
+	}
+}
+
+func TestPatternSpecBadJSON(t *testing.T) {
+	_, err := LoadPatternRegistry(strings.NewReader(`{"pattern": 5`))
+	assert.Error(t, err)
+}
@@ -35,6 +35,42 @@ func TestPanicOnPhaseProgressionInvalid(t *testing.T) {
 	PricePattern(10).PhaseProgression(nil)
 }
 
+func TestPatternErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	name, err := BIGSPIKE.Name()
+	assert.NoError(err)
+	assert.Equal("BIG SPIKE", name)
+
+	_, err = PricePattern(10).Name()
+	assert.Equal(errs.ErrBadPatternIndex, err)
+	assert.Equal("PricePattern(10)", PricePattern(10).String())
+	assert.Equal("PricePattern(-1)", PricePattern(-1).String())
+
+	chance, err := SMALLSPIKE.ChanceAfter(DECREASING)
+	assert.NoError(err)
+	assert.Equal(0.25, chance)
+
+	_, err = UNKNOWN.ChanceAfter(FLUCTUATING)
+	assert.Equal(errs.ErrUnknownBaseChanceInvalid, err)
+	_, err = PricePattern(5).ChanceAfter(FLUCTUATING)
+	assert.Equal(errs.ErrBadPatternIndex, err)
+	_, err = FLUCTUATING.ChanceAfter(PricePattern(-1))
+	assert.Equal(errs.ErrBadPatternIndex, err)
+
+	phases, err := DECREASING.Progression(new(PriceTicker))
+	assert.NoError(err)
+	assert.Len(phases, 1)
+
+	_, err = UNKNOWN.Progression(nil)
+	assert.Equal(errs.ErrUnknownPhasesInvalid, err)
+	_, err = PricePattern(10).Progression(nil)
+	assert.Equal(errs.ErrBadPatternIndex, err)
+
+	assert.Equal(0, UNKNOWN.PermutationCount())
+	assert.Equal(0, PricePattern(10).PermutationCount())
+}
+
 func TestPatternFromString(t *testing.T) {
 	type testCase struct {
 		StringVal string
@@ -177,10 +177,9 @@ func TestBaseChances(t *testing.T) {
 	for _, thisCase := range testCases {
 
 		test := func(t *testing.T) {
-			assert.Equal(
-				t,
-				thisCase.chanceExpected,
-				thisCase.thisWeek.BaseChance(thisCase.previousWeek))
+			chance, err := thisCase.thisWeek.ChanceAfter(thisCase.previousWeek)
+			assert.NoError(t, err)
+			assert.Equal(t, thisCase.chanceExpected, chance)
 		}
 
 		t.Run(
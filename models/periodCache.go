@@ -0,0 +1,90 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/values"
+	"sync"
+)
+
+// The price range of a phase's period only depends on the phase, where it starts, the
+// purchase price, and the prices the ticker has for the periods of the phase before
+// it. Tickers that share those can share the periods we work out for them, so a batch
+// of predictions does not need to compute the same periods over and over.
+type periodCacheKey struct {
+	definition    *PatternDefinition
+	phaseIndex    int
+	phaseLength   int
+	startPeriod   PricePeriod
+	subPeriod     int
+	purchasePrice int
+	// The ticker's observations for the periods of the phase before this one. Every
+	// other period is left empty.
+	observations PriceIntervalArray
+}
+
+// periodCache holds potential price periods to be shared between predictions. It is
+// safe for concurrent use.
+type periodCache struct {
+	lock    sync.RWMutex
+	periods map[periodCacheKey]*PotentialPricePeriod
+}
+
+// Copies the cached period for the key into ``period``. Returns false if there is
+// not one. The caller needs to point the period's PatternPhase at it's own phase.
+func (cache *periodCache) get(key periodCacheKey, period *PotentialPricePeriod) bool {
+	cache.lock.RLock()
+	cached, ok := cache.periods[key]
+	cache.lock.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	// The price information of a period is never changed once it is made, so we can
+	// copy it without holding the lock.
+	*period = *cached
+	return true
+}
+
+func (cache *periodCache) set(key periodCacheKey, period *PotentialPricePeriod) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.periods[key] = period
+}
+
+func newPeriodCache() *periodCache {
+	return &periodCache{
+		periods: make(map[periodCacheKey]*PotentialPricePeriod),
+	}
+}
+
+// Where a phase sits in a pattern, so we can look up it's periods in a periodCache.
+type phaseCacheInfo struct {
+	cache      *periodCache
+	definition *PatternDefinition
+	phaseIndex int
+}
+
+// Builds the cache key for a sub period of a phase.
+func (info *phaseCacheInfo) key(
+	phase *patternPhaseAuto, startPeriod PricePeriod, subPeriod int,
+) periodCacheKey {
+	ticker := phase.Ticker()
+	key := periodCacheKey{
+		definition:    info.definition,
+		phaseIndex:    info.phaseIndex,
+		phaseLength:   phase.Length(),
+		startPeriod:   startPeriod,
+		subPeriod:     subPeriod,
+		purchasePrice: ticker.PurchasePrice,
+	}
+
+	for period := startPeriod; period < startPeriod+PricePeriod(subPeriod); period++ {
+		if period >= values.PricePeriodCount {
+			break
+		}
+		low, high, _ := ticker.observation(period)
+		key.observations[period] = PriceInterval{Min: low, Max: high}
+	}
+
+	return key
+}
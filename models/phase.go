@@ -56,6 +56,19 @@ type PatternPhase interface {
 	Duplicate() PatternPhase
 }
 
+// The names the phases of the in-game patterns report from PatternPhase.Name(). The
+// locale catalogs have readable names for them.
+const (
+	PhaseSteadyDecrease   = "steady decrease"
+	PhaseSharpIncrease    = "sharp increase"
+	PhaseSharpDecrease    = "sharp decrease"
+	PhaseRandomLow        = "random low"
+	PhaseConstantDecrease = "whomp whomp"
+	PhaseMildIncrease     = "mild increase"
+	PhaseMildDecrease     = "mild decrease"
+	PhaseSmallSpike       = "small spike"
+)
+
 // These are the methods we need to implement in order to be wrapped by
 // phaseCoreAuto, which has default implementations for reporting prices.
 //
@@ -120,4 +133,15 @@ type phaseMakesFinalAdjustment interface {
 // just one bell less than the peak price itself.
 type phaseHasSpike interface {
 	IsSpike(subPeriod int) (isSpike bool, isBig bool)
+
+	// The sub period the spike reaches its highest price. For small spikes, this is
+	// the middle of the three spiked periods, as the periods to either side are capped
+	// at one bell less than the peak.
+	PeakSubPeriod() int
+}
+
+// Phases built from a PhaseDefinition implement every optional interface above, and
+// implement this interface to switch off the ones their definition does not use.
+type phaseConfiguresGenerator interface {
+	configureGenerator(gen *phasePeriodGenerator)
 }
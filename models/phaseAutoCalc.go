@@ -4,76 +4,137 @@ package models
 // implementation and get price period calculations for free
 type patternPhaseAuto struct {
 	phaseImplement
-	// For compounding phases, we have to generate
-	potentialPeriods []*PotentialPricePeriod
+	// For compounding phases, we have to generate each period from the one before it,
+	// so we keep them as we go. All the periods of the phase are allocated at once,
+	// rather than one at a time.
+	potentialPeriods []PotentialPricePeriod
+	// The sub periods of potentialPeriods that have been filled in.
+	filledPeriods periodSet
 
-	// whether or not the purchase price is known
-	purchasePriceKnown bool
+	// Generator object that calculates the next period's price on demand.
+	pricePeriodGen phasePeriodGenerator
 
-	// Generator objects that calculate the next period's price on demand.
-	pricePeriodGen    *phasePeriodGenerator
-	pricePeriodGenMax *phasePeriodGenerator
-}
-
-func (phase *patternPhaseAuto) setup(period PricePeriod, subPeriod int) {
-	phase.potentialPeriods = make([]*PotentialPricePeriod, phase.MaxLength())
-	phase.purchasePriceKnown = phase.Ticker().PurchasePrice != 0
+	// Shares periods with other predictions in a batch. nil if not predicting as part
+	// of a batch.
+	cacheInfo *phaseCacheInfo
 
-	phaseStartPeriod := period - PricePeriod(subPeriod)
-	purchasePrice := phase.Ticker().PurchasePrice
-	if !phase.purchasePriceKnown {
-		purchasePrice = 90
-	}
+	// Phases are reachable from the periods of a finished prediction, which may be
+	// shared between goroutines. Once a prediction is finished its phases are frozen:
+	// every period has been worked out, and the phase is never written to again.
+	frozen bool
+}
 
-	phase.pricePeriodGen = &phasePeriodGenerator{
+// Makes a generator for the phase starting on ``period`` - ``subPeriod``.
+func (phase *patternPhaseAuto) newGenerator(
+	period PricePeriod, subPeriod int,
+) phasePeriodGenerator {
+	// The predictor works through each possible purchase price when it is not known,
+	// so the ticker we are given always has one.
+	gen := phasePeriodGenerator{
 		Ticker:           phase.Ticker(),
-		PurchasePrice:    purchasePrice,
+		PurchasePrice:    phase.Ticker().PurchasePrice,
 		PhaseFull:        phase,
-		PricePeriodStart: phaseStartPeriod,
+		PricePeriodStart: period - PricePeriod(subPeriod),
 	}
-	phase.pricePeriodGen.Setup()
-
-	// If the purchase price is unknown we need to get the possibilities twice, once
-	// for the min price and once for the max price.
-	if !phase.purchasePriceKnown {
-		phase.pricePeriodGenMax = &phasePeriodGenerator{
-			Ticker:           phase.Ticker(),
-			PurchasePrice:    110,
-			PhaseFull:        phase,
-			PricePeriodStart: phaseStartPeriod,
+	gen.Setup()
+	return gen
+}
+
+func (phase *patternPhaseAuto) setup(period PricePeriod, subPeriod int) {
+	// Weeks we have already built may still point to the old periods, so we need new
+	// ones rather than writing over them.
+	phase.potentialPeriods = make([]PotentialPricePeriod, phase.MaxLength())
+	phase.filledPeriods = 0
+	phase.pricePeriodGen = phase.newGenerator(period, subPeriod)
+}
+
+// Works out every period of the phase for the price period it last started on, then
+// freezes the phase so it is safe to read from many goroutines at once.
+func (phase *patternPhaseAuto) freeze() {
+	if phase.frozen {
+		return
+	}
+	if phase.potentialPeriods != nil {
+		startPeriod := phase.pricePeriodGen.PricePeriodStart
+		for subPeriod := 0; subPeriod < phase.Length(); subPeriod++ {
+			phase.PotentialPeriod(startPeriod+PricePeriod(subPeriod), subPeriod)
 		}
-		phase.pricePeriodGenMax.Setup()
 	}
+	phase.frozen = true
+}
+
+// Works out a period of a frozen phase that it does not have, without changing the
+// phase.
+func (phase *patternPhaseAuto) generatePeriod(
+	period PricePeriod, subPeriod int,
+) *PotentialPricePeriod {
+	gen := phase.newGenerator(period, subPeriod)
+	potentialPeriod := new(PotentialPricePeriod)
+	for i := 0; i <= subPeriod; i++ {
+		gen.Next(potentialPeriod)
+	}
+	return potentialPeriod
 }
 
 func (phase *patternPhaseAuto) PotentialPeriod(
 	period PricePeriod, subPeriod int,
 ) *PotentialPricePeriod {
-	// Set up our cache if needed
-	if phase.potentialPeriods == nil {
+	startPeriod := period - PricePeriod(subPeriod)
+	if phase.frozen {
+		if phase.potentialPeriods != nil &&
+			phase.pricePeriodGen.PricePeriodStart == startPeriod &&
+			phase.filledPeriods.has(PricePeriod(subPeriod)) {
+			return &phase.potentialPeriods[subPeriod]
+		}
+		return phase.generatePeriod(period, subPeriod)
+	}
+
+	// Set up our cache if needed. A phase that was finalized before a branch is shared
+	// by every week that branches off of it, and may start on a different price period
+	// in each one (fluctuating's second decreasing phase, for instance, moves with the
+	// length of the last increasing phase). The cache is only good for one start
+	// period, so we need to start over if it changes.
+	if phase.potentialPeriods == nil ||
+		phase.pricePeriodGen.PricePeriodStart != startPeriod {
 		phase.setup(period, subPeriod)
 	}
 
 	// See if this period is in our cache
-	potentialPeriod := phase.potentialPeriods[subPeriod]
-	if potentialPeriod != nil {
+	potentialPeriod := &phase.potentialPeriods[subPeriod]
+	if phase.filledPeriods.has(PricePeriod(subPeriod)) {
 		// If it is, return it.
 		return potentialPeriod
 	}
 
+	// Another prediction in the batch may have already worked this period out.
+	var cacheKey periodCacheKey
+	if phase.cacheInfo != nil {
+		cacheKey = phase.cacheInfo.key(phase, startPeriod, subPeriod)
+		if phase.cacheInfo.cache.get(cacheKey, potentialPeriod) {
+			potentialPeriod.PatternPhase = phase
+			phase.filledPeriods.add(PricePeriod(subPeriod))
+			return potentialPeriod
+		}
+	}
+
 	// If not, we are going to generate price periods through our price generator until
-	// we get to the period we want.
+	// we get to the period we want. Periods that came from the batch cache did not
+	// move the generator along, so it may need to go over them again. They may already
+	// be in use, so we don't write over them.
+	var skipped PotentialPricePeriod
 	for i := phase.pricePeriodGen.LastCompletedSubPeriod + 1; i <= subPeriod; i++ {
-		potentialPeriod = phase.pricePeriodGen.Next()
-
-		// If the price is unknown get the next max possibility and adjust the max
-		// values.
-		if !phase.purchasePriceKnown {
-			potentialPeriodMax := phase.pricePeriodGenMax.Next()
-			potentialPeriod.maxPrice = potentialPeriodMax.maxPrice
-			potentialPeriod.maxChance = potentialPeriodMax.maxChance
+		if phase.filledPeriods.has(PricePeriod(i)) {
+			phase.pricePeriodGen.Next(&skipped)
+			continue
 		}
-		phase.potentialPeriods[i] = potentialPeriod
+		phase.pricePeriodGen.Next(&phase.potentialPeriods[i])
+		phase.filledPeriods.add(PricePeriod(i))
+	}
+
+	// Filled periods are never written to again, so other predictions can read this
+	// one straight from our phase.
+	if phase.cacheInfo != nil {
+		phase.cacheInfo.cache.set(cacheKey, potentialPeriod)
 	}
 
 	return potentialPeriod
@@ -84,5 +145,6 @@ func (phase *patternPhaseAuto) Duplicate() PatternPhase {
 		phaseImplement: phase.phaseImplement.Duplicate(),
 		// This cache will need to be created for each phase p
 		potentialPeriods: nil,
+		cacheInfo:        phase.cacheInfo,
 	}
 }
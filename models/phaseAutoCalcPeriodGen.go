@@ -17,7 +17,7 @@ import (
 // a phase is a bad match for the ticker, we would have done up to 6 price period
 // calculations we would then just throw away
 //
-// Enter the phasePeriodGenerator. Acts as an iterator-like object that will yield
+// Enter the phasePeriodGenerator. Acts as an iterator-like object that will fill in
 // the next PotentialPricePeriod when it's .Next() method is called. Internally, it
 // keeps all the data we would have in a loop in order to calculate the next price
 // period, thus allowing us to NOT re-calculate all past sup-periods when a new one
@@ -38,6 +38,10 @@ type phasePeriodGenerator struct {
 	makesFinal  phaseMakesFinalAdjustment
 	hasSpike    phaseHasSpike
 
+	// Whether the phase compounds downwards, so each sub period after the first is
+	// no higher than the one before it.
+	compoundingFalls bool
+
 	// Data fields - these fields will be updated during processing
 	// Whether this iterator has been started
 	started bool
@@ -53,7 +57,10 @@ type phasePeriodGenerator struct {
 
 	finalAdjustment int
 
-	previousPeriodPrice int
+	// The lowest and highest price the previous period could have been, from the
+	// ticker. 0 if not known.
+	previousPeriodLow  int
+	previousPeriodHigh int
 
 	priceMin int
 	priceMax int
@@ -75,6 +82,11 @@ func (gen *phasePeriodGenerator) Setup() {
 	if hasSpike, ok := gen.phase.(phaseHasSpike); ok {
 		gen.hasSpike = hasSpike
 	}
+	if configures, ok := gen.phase.(phaseConfiguresGenerator); ok {
+		configures.configureGenerator(gen)
+	}
+	gen.compoundingFalls = gen.compounding != nil &&
+		gen.compounding.AdjustPriceMultiplier(1, false) <= 1
 
 	gen.LastCompletedSubPeriod = -1
 	gen.started = true
@@ -105,7 +117,16 @@ func (gen *phasePeriodGenerator) beginThisIteration() {
 func (gen *phasePeriodGenerator) endThisIteration() {
 	// if we are beyond phase one, set the previous price so the compounding
 	// calculations have access to it.
-	gen.previousPeriodPrice = gen.Ticker.Prices[gen.pricePeriod]
+	gen.previousPeriodLow, gen.previousPeriodHigh, _ = gen.Ticker.observation(
+		gen.pricePeriod,
+	)
+	// A price that could not have come from this period must be a mistake (otherwise
+	// the week would have been thrown out), so we can't learn anything from it.
+	if (gen.previousPeriodLow != 0 && gen.previousPeriodLow > gen.priceMax) ||
+		(gen.previousPeriodHigh != 0 && gen.previousPeriodHigh < gen.priceMin) {
+		gen.previousPeriodLow = 0
+		gen.previousPeriodHigh = 0
+	}
 	gen.LastCompletedSubPeriod = gen.subPeriod
 	gen.subPeriod++
 	gen.pricePeriod++
@@ -115,7 +136,10 @@ func (gen *phasePeriodGenerator) endThisIteration() {
 func (gen *phasePeriodGenerator) calcPhasePeriodHistoricalMultiplier(
 	isMin bool,
 ) (historicMultiplier float32) {
-	previousPrice := gen.previousPeriodPrice
+	previousPrice := gen.previousPeriodHigh
+	if isMin {
+		previousPrice = gen.previousPeriodLow
+	}
 	// Un-adjust this price if it has an adjustment (price adjustments
 	// never happen in the actual game during compounding phases, but we'll
 	// put it here for max compatibility in case that ever changes with an
@@ -173,6 +197,13 @@ func (gen *phasePeriodGenerator) calcNookPriceAndWidth(
 	binWidth = float64(price) -
 		(float64(gen.PurchasePrice) * float64(priceMultiplier))
 
+	// The price was rounded using float32 math like the game, so the more precise
+	// unrounded price can come out a hair over it. There's no chance of getting a
+	// price we would have to round down to, so the width can't be less than 0.
+	if binWidth < 0 {
+		binWidth = 0
+	}
+
 	return price, binWidth
 }
 
@@ -199,10 +230,15 @@ func (gen *phasePeriodGenerator) adjustCompounding() {
 	// world price by the upper and lower sub-period bounds. Each time we know
 	// the real price for the previous price period, we are going to reset the
 	// multiplier to
-	if gen.previousPeriodPrice != 0 {
+	//
+	// If we only know the previous price fell within an interval, we can only narrow
+	// the ends of the range the interval has bounds for.
+	if gen.previousPeriodLow != 0 {
 		gen.historicalMultiplierMin = gen.calcPhasePeriodHistoricalMultiplier(
 			true,
 		)
+	}
+	if gen.previousPeriodHigh != 0 {
 		gen.historicalMultiplierMax = gen.calcPhasePeriodHistoricalMultiplier(
 			false,
 		)
@@ -240,7 +276,8 @@ func (gen *phasePeriodGenerator) adjustCompounding() {
 	gen.binWidthMax *= subBinWidthMax
 }
 
-func (gen *phasePeriodGenerator) buildCurrentPeriod() *PotentialPricePeriod {
+// Fills in ``period`` with the current period's prices.
+func (gen *phasePeriodGenerator) buildCurrentPeriod(period *PotentialPricePeriod) {
 	possibilityCount := gen.priceMax - gen.priceMin + 1
 
 	// Every  number  that is not the min or max has a width of 1, so the total width
@@ -257,14 +294,14 @@ func (gen *phasePeriodGenerator) buildCurrentPeriod() *PotentialPricePeriod {
 	midChance := midWidth / totalWidth
 	maxChance := maxWidth / totalWidth
 
-	var isSpike, isBigSpike, isSmallSpike bool
+	var isSpike, isBigSpike, isPeak bool
 	if gen.hasSpike != nil {
 		isSpike, isBigSpike = gen.hasSpike.IsSpike(gen.subPeriod)
-		isSmallSpike = isSpike && !isBigSpike
+		isPeak = isSpike && gen.subPeriod == gen.hasSpike.PeakSubPeriod()
 	}
 
-	return &PotentialPricePeriod{
-		pricesVal: &pricesVal{
+	*period = PotentialPricePeriod{
+		pricesVal: pricesVal{
 			minPrice:        gen.priceMin,
 			guaranteedPrice: gen.priceMin,
 			maxPrice:        gen.priceMax,
@@ -273,24 +310,15 @@ func (gen *phasePeriodGenerator) buildCurrentPeriod() *PotentialPricePeriod {
 			midChance: midChance,
 			maxChance: maxChance,
 		},
-		Spikes: &SpikeHasAll{
-			any: &Spike{
-				has: isSpike,
-			},
-			big: &Spike{
-				has: isBigSpike,
-			},
-			small: &Spike{
-				has: isSmallSpike,
-			},
-		},
+		Spikes:       spikeFlags(isSpike, isBigSpike, isPeak),
 		PricePeriod:  gen.pricePeriod,
 		PatternPhase: gen.PhaseFull,
+		falls:        gen.compoundingFalls && gen.subPeriod > 0,
 	}
 }
 
-// Yields the next potential price period.
-func (gen *phasePeriodGenerator) Next() *PotentialPricePeriod {
+// Fills in ``period`` with the next potential price period.
+func (gen *phasePeriodGenerator) Next(period *PotentialPricePeriod) {
 	// Do some set up work to start this iteration
 	gen.beginThisIteration()
 
@@ -321,9 +349,7 @@ func (gen *phasePeriodGenerator) Next() *PotentialPricePeriod {
 	gen.priceMin += gen.finalAdjustment
 	gen.priceMax += gen.finalAdjustment
 
-	potentialPeriod := gen.buildCurrentPeriod()
+	gen.buildCurrentPeriod(period)
 
 	gen.endThisIteration()
-
-	return potentialPeriod
 }
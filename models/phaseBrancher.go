@@ -0,0 +1,125 @@
+package models
+
+import "github.com/peake100/turnup-go/values"
+
+// Works through every possible combination of phase lengths for a phase progression,
+// handing each fully formed combination to onComplete. Used by the pattern predictor
+// to build potential weeks, and by the pattern registry to count the permutations of a
+// pattern.
+type phaseBrancher struct {
+	onComplete func(patternPhases []PatternPhase)
+}
+
+// Makes a duplicate of the current phase pattern to be a new possibility
+func (brancher *phaseBrancher) duplicatePhasePattern(
+	patternPhases []PatternPhase,
+) []PatternPhase {
+
+	dupedPhases := make([]PatternPhase, len(patternPhases))
+	for i, phase := range patternPhases {
+		var branchPhase PatternPhase
+
+		// If the phase is finalized, we don't need to copy it. It's values will not
+		// be changing.
+		if phase.IsFinal() {
+			branchPhase = phase
+		} else {
+			branchPhase = phase.Duplicate()
+		}
+
+		dupedPhases[i] = branchPhase
+	}
+	return dupedPhases
+}
+
+// Creates a new branch and calculates it's possible permutations
+func (brancher *phaseBrancher) computeBranch(
+	thisPossibleLength int,
+	possibilityIndex int,
+	phaseIndex int,
+	allPossibleLengths []int,
+	patternPhases []PatternPhase,
+) {
+	var newBranch []PatternPhase
+	if possibilityIndex < len(allPossibleLengths)-1 {
+		// duplicate our current pattern so we can set the possible length
+		// for this phase
+		newBranch = brancher.duplicatePhasePattern(patternPhases)
+	} else {
+		// If this is the last possible length, we can just re-use the current
+		// branch rather than making a new duplicate and throwing away our
+		// current
+		newBranch = patternPhases
+	}
+
+	// set the branch phases' length to this possibility
+	newBranch[phaseIndex].SetLength(thisPossibleLength)
+	// Tell the work group we are adding a new branch we need to wait for
+	// Launch the branch
+	brancher.branchPhases(newBranch)
+}
+
+// Reports a fully formed phase combination. Phase definitions can describe length
+// combinations that do not add up to a full week, so we drop those here rather than
+// building a potential week with missing price periods.
+func (brancher *phaseBrancher) complete(patternPhases []PatternPhase) {
+	totalLength := 0
+	for _, phase := range patternPhases {
+		totalLength += phase.Length()
+	}
+	if totalLength != values.PricePeriodCount {
+		return
+	}
+
+	brancher.onComplete(patternPhases)
+}
+
+// Takes an array of pattern phases and recursively works through all un-computed
+// possible phase length patterns.
+func (brancher *phaseBrancher) branchPhases(
+	patternPhases []PatternPhase,
+) {
+	// To figure out the pattern for a week, we need to find all the possible lengths
+	// for each phase, then make a copy of the phase pattern with that possibility
+	// set to be re-iterated over in a new routine. We continue until all possibilities
+	// in all goroutines have reported they are finalized.
+	//
+	// There is no variance in the price pattern of each phase, only in how long the
+	// phase lasts. So if we have all possible combinations of phase lengths, then we
+	// have all possible price patterns.
+	for phaseIndex, phase := range patternPhases {
+
+		// If this phase has it's final length set, we can continue to the next phase.
+		if phase.IsFinal() {
+			continue
+		}
+
+		// Get all the possible lengths for this phase,
+		possibleLengths := phase.PossibleLengths(patternPhases)
+		// If the possibilities are nil, then this phase is waiting for more
+		// information, and we should continue to the next phase
+		if possibleLengths == nil {
+			continue
+		}
+
+		// Otherwise we need to create a new possible pattern branch for each phase
+		// length and branch off of it.
+		for i, phaseLength := range possibleLengths {
+			brancher.computeBranch(
+				phaseLength,
+				i,
+				phaseIndex,
+				possibleLengths,
+				patternPhases,
+			)
+		}
+
+		// We don't have a complete branch if we are permutating possibilities, so
+		// return
+		return
+	}
+
+	// If we make it all the way through than we have hit a fully formed possible phase
+	// pattern! Now we can compute the possible prices and return them as the result
+	brancher.complete(patternPhases)
+}
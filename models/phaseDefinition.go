@@ -0,0 +1,283 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/values"
+	"golang.org/x/xerrors"
+)
+
+// MultiplierRange is the min and max multiplier of the purchase price for a sub period
+// of a phase.
+type MultiplierRange struct {
+	Min float32 `json:"min"`
+	Max float32 `json:"max"`
+}
+
+// Compounding describes a phase whose price multiplier drifts from one sub period to
+// the next rather than being set fresh each sub period. Only the first multiplier of
+// the phase is used, and the deltas are then added to the multiplier once per sub
+// period after the first.
+//
+// The deltas are added one at a time, in order. In order to match the EXACT
+// calculations from the game, a delta of -0.05 that the game computes as -0.02 then
+// -0.03 needs to be described as two deltas, otherwise we end up with a SLIGHTLY
+// different float value.
+type Compounding struct {
+	Min []float32 `json:"min"`
+	Max []float32 `json:"max"`
+}
+
+// SpikeMarker marks the sub periods of a phase that count as a price spike.
+type SpikeMarker struct {
+	// The first and last spiked sub periods, inclusive.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Whether this is a big spike. Small spikes otherwise.
+	Big bool `json:"big"`
+	// The sub period the spike reaches its highest price.
+	Peak int `json:"peak"`
+}
+
+// LengthRule works out the possible lengths of a phase from the other phases in its
+// progression.
+type LengthRule interface {
+	// Returns the possible lengths for the phase, or nil if the phases
+	// the rule depends on do not have their final lengths yet. Returns an empty,
+	// non-nil slice if there are no possible lengths.
+	PossibleLengths(phases []PatternPhase) []int
+}
+
+// LengthChoices is a fixed list of possible lengths.
+type LengthChoices []int
+
+func (choices LengthChoices) PossibleLengths([]PatternPhase) []int {
+	lengths := make([]int, len(choices))
+	copy(lengths, choices)
+	return lengths
+}
+
+// LengthExpr is a single length worked out from the lengths of other phases: Base
+// minus the length of each phase index in Minus.
+type LengthExpr struct {
+	Base  int   `json:"base"`
+	Minus []int `json:"minus,omitempty"`
+}
+
+// Returns the value of the expression and whether all the phases it depends on are
+// final.
+func (expr LengthExpr) value(phases []PatternPhase) (length int, ok bool) {
+	length = expr.Base
+	for _, index := range expr.Minus {
+		phase := phases[index]
+		if !phase.IsFinal() {
+			return 0, false
+		}
+		length -= phase.Length()
+	}
+	return length, true
+}
+
+func (expr LengthExpr) PossibleLengths(phases []PatternPhase) []int {
+	length, ok := expr.value(phases)
+	if !ok {
+		return nil
+	}
+	if length < 0 {
+		return []int{}
+	}
+	return []int{length}
+}
+
+func (expr LengthExpr) references() []int {
+	return expr.Minus
+}
+
+// LengthRange is every length from Min through Max, inclusive.
+type LengthRange struct {
+	Min LengthExpr
+	Max LengthExpr
+}
+
+func (lengthRange LengthRange) PossibleLengths(phases []PatternPhase) []int {
+	low, lowOk := lengthRange.Min.value(phases)
+	high, highOk := lengthRange.Max.value(phases)
+	if !lowOk || !highOk {
+		return nil
+	}
+	if low < 0 {
+		low = 0
+	}
+
+	lengths := make([]int, 0)
+	for length := low; length <= high; length++ {
+		lengths = append(lengths, length)
+	}
+	return lengths
+}
+
+func (lengthRange LengthRange) references() []int {
+	references := make([]int, 0, len(lengthRange.Min.Minus)+len(lengthRange.Max.Minus))
+	references = append(references, lengthRange.Min.Minus...)
+	return append(references, lengthRange.Max.Minus...)
+}
+
+// Implemented by length rules that depend on the lengths of other phases. Returns the
+// indexes of the phases the rule depends on.
+type lengthRuleReferences interface {
+	references() []int
+}
+
+// PhaseDefinition describes a phase out of simple building blocks, so new patterns can
+// be added without implementing PatternPhase by hand.
+type PhaseDefinition struct {
+	Name string
+
+	// The multipliers for each sub period of the phase. If the phase runs longer than
+	// this slice, the last multiplier is used for the remaining sub periods.
+	Multipliers []MultiplierRange
+
+	// Set if the multiplier compounds from one sub period to the next.
+	Compounding *Compounding
+
+	// The possible lengths of this phase.
+	Length LengthRule
+
+	// Bells added to the price of a sub period after rounding, by sub period.
+	FinalAdjustments map[int]int
+
+	// Set if this phase has a price spike in it.
+	Spike *SpikeMarker
+}
+
+// Validates the definition of the phase at ``index`` of a progression with
+// ``phaseCount`` phases.
+func (definition *PhaseDefinition) validate(index int, phaseCount int) error {
+	if definition.Name == "" {
+		return xerrors.Errorf("phase has no name: %w", errs.ErrInvalidPatternDefinition)
+	}
+	if len(definition.Multipliers) == 0 {
+		return xerrors.Errorf(
+			"phase '%v' has no multipliers: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+	if definition.Length == nil {
+		return xerrors.Errorf(
+			"phase '%v' has no length rule: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+	if references, ok := definition.Length.(lengthRuleReferences); ok {
+		for _, reference := range references.references() {
+			if reference < 0 || reference >= phaseCount || reference == index {
+				return xerrors.Errorf(
+					"phase '%v' length depends on invalid phase index %v: %w",
+					definition.Name,
+					reference,
+					errs.ErrInvalidPatternDefinition,
+				)
+			}
+		}
+	}
+	if compounding := definition.Compounding; compounding != nil &&
+		(len(compounding.Min) == 0 || len(compounding.Max) == 0) {
+		return xerrors.Errorf(
+			"phase '%v' compounds without min and max deltas: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+	if spike := definition.Spike; spike != nil &&
+		(spike.Start > spike.End || spike.Peak < spike.Start || spike.Peak > spike.End) {
+		return xerrors.Errorf(
+			"phase '%v' spike peak must fall within the spike: %w",
+			definition.Name,
+			errs.ErrInvalidPatternDefinition,
+		)
+	}
+	return nil
+}
+
+// Implements phaseImplement, along with every optional phase interface, from a
+// PhaseDefinition. The optional interfaces the definition does not use are switched
+// off through configureGenerator().
+type definedPhase struct {
+	phaseCoreAuto
+	definition *PhaseDefinition
+}
+
+func (phase *definedPhase) Name() string {
+	return phase.definition.Name
+}
+
+func (phase *definedPhase) PossibleLengths(
+	phases []PatternPhase,
+) (possibilities []int) {
+	possibilities = phase.definition.Length.PossibleLengths(phases)
+	if possibilities != nil {
+		phase.PossibilitiesComplete()
+	}
+	return possibilities
+}
+
+func (phase *definedPhase) MaxLength() int {
+	return values.PricePeriodCount
+}
+
+func (phase *definedPhase) BasePriceMultiplier(
+	subPeriod int,
+) (min float32, max float32) {
+	multipliers := phase.definition.Multipliers
+	if subPeriod >= len(multipliers) {
+		subPeriod = len(multipliers) - 1
+	}
+	return multipliers[subPeriod].Min, multipliers[subPeriod].Max
+}
+
+func (phase *definedPhase) AdjustPriceMultiplier(factor float32, isMin bool) float32 {
+	deltas := phase.definition.Compounding.Max
+	if isMin {
+		deltas = phase.definition.Compounding.Min
+	}
+	for _, delta := range deltas {
+		factor += delta
+	}
+	return factor
+}
+
+func (phase *definedPhase) FinalPriceAdjustment(subPeriod int) int {
+	return phase.definition.FinalAdjustments[subPeriod]
+}
+
+func (phase *definedPhase) IsSpike(subPeriod int) (isSpike bool, isBig bool) {
+	spike := phase.definition.Spike
+	if subPeriod < spike.Start || subPeriod > spike.End {
+		return false, false
+	}
+	return true, spike.Big
+}
+
+func (phase *definedPhase) PeakSubPeriod() int {
+	return phase.definition.Spike.Peak
+}
+
+func (phase *definedPhase) configureGenerator(gen *phasePeriodGenerator) {
+	if phase.definition.Compounding == nil {
+		gen.compounding = nil
+	}
+	if len(phase.definition.FinalAdjustments) == 0 {
+		gen.makesFinal = nil
+	}
+	if phase.definition.Spike == nil {
+		gen.hasSpike = nil
+	}
+}
+
+func (phase *definedPhase) Duplicate() phaseImplement {
+	return &definedPhase{
+		phaseCoreAuto: phase.phaseCoreAuto,
+		definition:    phase.definition,
+	}
+}
@@ -20,7 +20,10 @@ func testPhaseNames(
 		assert.Equal(t, name, phase.Name(), "phase name")
 	}
 
-	phaseList := pattern.PhaseProgression(priceTicker)
+	phaseList, err := pattern.Progression(priceTicker)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
 
 	for i, phase = range phaseList {
 		name = expectedNames[i]
@@ -66,7 +69,7 @@ func TestPatternPhaseProgression(t *testing.T) {
 			SMALLSPIKE,
 			[]string{
 				"steady decrease",
-				"small hasSpikeAny",
+				"small spike",
 				"steady decrease",
 			},
 		},
@@ -84,16 +87,109 @@ func TestPatternPhaseProgression(t *testing.T) {
 
 }
 
-// Tests that we panic if we try to duplicate the decreasing pattern phase
-func TestPhaseDecreasingDuplicatePanics(t *testing.T) {
-	defer func() {
-		recovered := recover()
-		err, ok := recovered.(error)
-		assert.True(t, ok, "recovered error")
-		assert.EqualError(
-			t, err, "decreasing phase should never be duplicated",
-		)
-	}()
-
-	new(decreasingPattern).Duplicate()
+// The decreasing pattern phase is never duplicated by the predictor, but duplicating it
+// should still work rather than panicking.
+func TestPhaseDecreasingDuplicate(t *testing.T) {
+	assert := assert.New(t)
+
+	phase := &patternPhaseAuto{phaseImplement: new(decreasingPattern)}
+	phase.SetTicker(NewTicker(100, UNKNOWN, 0))
+	phase.SetLength(12)
+
+	var duplicate PatternPhase
+	assert.NotPanics(func() {
+		duplicate = phase.Duplicate()
+	})
+	assert.Equal(phase.Name(), duplicate.Name())
+	assert.Equal(phase.Length(), duplicate.Length())
+	assert.Same(phase.Ticker(), duplicate.(*patternPhaseAuto).Ticker())
+}
+
+// Fluctuating's second decreasing phase has its length set before the length of the
+// last increasing phase is known, so it is shared by weeks where it starts on
+// different price periods. Each week should still get the prices for its own start.
+func TestSharedPhaseStartPeriods(t *testing.T) {
+	assert := assert.New(t)
+
+	// Decreasing on thursday PM and friday AM, then increasing for the rest of the
+	// week.
+	ticker := NewTicker(102, UNKNOWN, 10)
+	ticker.Prices[9] = 65
+	ticker.Prices[10] = 105
+
+	prediction, err := (&Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	found := false
+	for _, week := range prediction.Patterns[FLUCTUATING].PotentialWeeks {
+		for period, potentialPeriod := range week.Prices {
+			price := ticker.Prices[period]
+			if price == 0 {
+				continue
+			}
+			assert.GreaterOrEqual(price, potentialPeriod.MinPrice(), "period", period)
+			assert.LessOrEqual(price, potentialPeriod.MaxPrice(), "period", period)
+		}
+
+		decreasing := week.Prices[8].PatternPhase.Name() == "mild decrease"
+		increasing := week.Prices[10].PatternPhase.Name() == "mild increase"
+		if decreasing && increasing {
+			found = true
+		}
+	}
+	assert.True(found, "week decreasing on thursday PM and friday AM")
+}
+
+// Prices at the very ends of a range are rounded using float32 math like the game,
+// which used to give them a bin width a hair under 0. That made the chance of those
+// prices negative, even when every price in the ticker is known to be right.
+func TestPeriodChancesNotNegative(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := (&Predictor{Ticker: NewTicker(100, UNKNOWN, 0)}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			for _, period := range week.Prices {
+				assert.GreaterOrEqual(period.minChance, 0.0, "min chance")
+				assert.GreaterOrEqual(period.midChance, 0.0, "mid chance")
+				assert.GreaterOrEqual(period.maxChance, 0.0, "max chance")
+			}
+		}
+	}
+}
+
+// Clamping negative bin widths to 0 only drops chances a hair under 0 from the prices
+// of a period. It does not change how likely any week is, so the chances of each
+// pattern for the benchmark fixtures are the same as before.
+func TestExactChancesForFixtures(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := map[string][4]float64{
+		"Empty":           {0.35, 0.2625, 0.1375, 0.25},
+		"UnknownPurchase": {0.0076, 0.2888, 0.1685, 0.5352},
+		"PartialWeek":     {0, 0.6613, 0, 0.3387},
+		"FullWeek":        {0, 1, 0, 0},
+		"NearImpossible":  {0, 0, 0, 1},
+	}
+
+	for _, fixture := range benchmarkFixtures() {
+		prediction, err := (&Predictor{Ticker: fixture.Ticker}).Predict()
+		if !assert.NoError(err, fixture.Name) {
+			continue
+		}
+		for _, pattern := range PATTERNSGAME {
+			assert.InDelta(
+				expected[fixture.Name][pattern],
+				prediction.Patterns[pattern].Chance(),
+				1e-12,
+				"%v %v", fixture.Name, pattern,
+			)
+		}
+	}
 }
@@ -6,7 +6,7 @@ type steadyDecrease struct {
 }
 
 func (phase *steadyDecrease) Name() string {
-	return "steady decrease"
+	return PhaseSteadyDecrease
 }
 
 func (phase *steadyDecrease) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -47,8 +47,12 @@ func (phase *sharpIncrease) IsSpike(subPeriod int) (isSpike bool, isBig bool) {
 	return false, false
 }
 
+func (phase *sharpIncrease) PeakSubPeriod() int {
+	return 2
+}
+
 func (phase *sharpIncrease) Name() string {
-	return "sharp increase"
+	return PhaseSharpIncrease
 }
 
 func (phase *sharpIncrease) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -85,7 +89,7 @@ type sharpDecrease struct {
 }
 
 func (phase *sharpDecrease) Name() string {
-	return "sharp decrease"
+	return PhaseSharpDecrease
 }
 
 func (phase *sharpDecrease) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -119,7 +123,7 @@ type randomDecrease struct {
 }
 
 func (phase *randomDecrease) Name() string {
-	return "random low"
+	return PhaseRandomLow
 }
 
 func (phase *randomDecrease) PossibleLengths(
@@ -1,7 +1,5 @@
 package models
 
-import "golang.org/x/xerrors"
-
 // We only need to implement a single phase for this, since the whole week follows one
 // pattern.
 type decreasingPattern struct {
@@ -9,7 +7,7 @@ type decreasingPattern struct {
 }
 
 func (phase *decreasingPattern) Name() string {
-	return "whomp whomp"
+	return PhaseConstantDecrease
 }
 
 func (phase *decreasingPattern) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -35,10 +33,11 @@ func (phase *decreasingPattern) AdjustPriceMultiplier(
 }
 
 func (phase *decreasingPattern) Duplicate() phaseImplement {
-	// We should never need to duplicate a decreasing stage because there is only ont
-	// price pattern for the decreasing pattern. We'll set it to panic if it get's
-	// accessed
-	panic(xerrors.New("decreasing phase should never be duplicated"))
+	// There is only one phase progression for the decreasing pattern, so the
+	// predictor never needs to duplicate this phase, but there is no harm in it.
+	return &decreasingPattern{
+		phase.phaseCoreAuto,
+	}
 }
 
 // Generates a new set of decreasing phases to branch possible weeks off of.
@@ -49,7 +49,7 @@ type increasing1 struct {
 }
 
 func (phase *increasing1) Name() string {
-	return "mild increase"
+	return PhaseMildIncrease
 }
 
 func (phase *increasing1) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -74,7 +74,7 @@ type decreasing1 struct {
 }
 
 func (phase *decreasing1) Name() string {
-	return "mild decrease"
+	return PhaseMildDecrease
 }
 
 func (phase *decreasing1) PossibleLengths([]PatternPhase) (possibilities []int) {
@@ -99,7 +99,7 @@ type increasing2 struct {
 }
 
 func (phase *increasing2) Name() string {
-	return "mild increase"
+	return PhaseMildIncrease
 }
 
 func (phase *increasing2) PossibleLengths(
@@ -150,7 +150,7 @@ type decreasing2 struct {
 }
 
 func (phase *decreasing2) Name() string {
-	return "mild decrease"
+	return PhaseMildDecrease
 }
 
 func (phase *decreasing2) PossibleLengths(
@@ -176,7 +176,7 @@ type increasing3 struct {
 }
 
 func (phase *increasing3) Name() string {
-	return "mild increase"
+	return PhaseMildIncrease
 }
 
 func (phase *increasing3) PossibleLengths(
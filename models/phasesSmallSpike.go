@@ -31,7 +31,7 @@ type smallSpikeDecreasing1 struct {
 }
 
 func (phase *smallSpikeDecreasing1) Name() string {
-	return "steady decrease"
+	return PhaseSteadyDecrease
 }
 
 func (phase *smallSpikeDecreasing1) PossibleLengths(
@@ -65,6 +65,11 @@ func (phase *smallSpikeIncreasing) IsSpike(subPeriod int) (isSpike bool, isBig b
 	return true, false
 }
 
+func (phase *smallSpikeIncreasing) PeakSubPeriod() int {
+	// Sub periods 2 and 4 are the "peak minus one" periods.
+	return 3
+}
+
 func (phase *smallSpikeIncreasing) FinalPriceAdjustment(subPeriod int) int {
 	if subPeriod == 2 || subPeriod == 4 {
 		return -1
@@ -83,7 +88,7 @@ func (phase *smallSpikeIncreasing) BasePriceMultiplier(
 }
 
 func (phase *smallSpikeIncreasing) Name() string {
-	return "small hasSpikeAny"
+	return PhaseSmallSpike
 }
 
 func (phase *smallSpikeIncreasing) PossibleLengths(
@@ -109,7 +114,7 @@ type smallSpikeDecreasing2 struct {
 }
 
 func (phase *smallSpikeDecreasing2) Name() string {
-	return "steady decrease"
+	return PhaseSteadyDecrease
 }
 
 func (phase *smallSpikeDecreasing2) PossibleLengths(
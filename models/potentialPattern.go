@@ -9,4 +9,16 @@ type PotentialPattern struct {
 	Pattern PricePattern
 	// The potential week's price patterns
 	PotentialWeeks []*PotentialWeek
+	// The probability distribution of prices for this pattern, weighted by the chance
+	// of each potential week.
+	Bands *PriceBands
+
+	// The definition this pattern was predicted from.
+	definition *PatternDefinition
+	// The potential periods for the latest price period a week of this pattern was
+	// thrown out on because the ticker's price did not fit, one for each phase that
+	// weeks were in. Empty if no weeks were thrown out.
+	ruledOutBy []*PotentialPricePeriod
+	// The number of weeks removed by the prediction's constraints.
+	excludedWeeks int
 }
@@ -1,7 +1,7 @@
 package models
 
 type PotentialPricePeriod struct {
-	*pricesVal
+	pricesVal
 	Spikes *SpikeHasAll
 
 	// The price period
@@ -9,6 +9,10 @@ type PotentialPricePeriod struct {
 
 	// The pattern phase used to generate this period.
 	PatternPhase PatternPhase
+
+	// Whether the price is the previous period's price compounded downwards, so it
+	// can't be higher than it.
+	falls bool
 }
 
 // Returns ``true`` if ``price`` falls within the price range of this potential period.
@@ -23,3 +27,13 @@ func (potential *PotentialPricePeriod) IsValidPrice(price int) bool {
 	return price >= potential.pricesVal.GuaranteedPrice() &&
 		price <= potential.pricesVal.MaxPrice()
 }
+
+// Returns ``true`` if any price from ``low`` to ``high`` (inclusive) falls within the
+// price range of this potential period. A bound of 0 is open-ended. Used by the
+// predictor to remove phase permutations that do not match price intervals.
+func (potential *PotentialPricePeriod) IsValidRange(low int, high int) bool {
+	if low != 0 && low > potential.pricesVal.MaxPrice() {
+		return false
+	}
+	return high == 0 || high >= potential.pricesVal.GuaranteedPrice()
+}
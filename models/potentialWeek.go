@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/peake100/turnup-go/models/timeofday"
+	"github.com/peake100/turnup-go/values"
 	"time"
 )
 
@@ -38,9 +39,36 @@ type PotentialWeek struct {
 	// Holds chance and price information
 	*Analysis
 
+	// The pattern this week belongs to.
+	Pattern PricePattern
+
+	// The sunday purchase price this week was predicted from. When the ticker does
+	// not have a purchase price, each possible price gets its own potential weeks.
+	PurchasePrice int
+
 	// Details about if and when a price spike could occur for this week.
 	Spikes *SpikeRangeAll
 
 	// Holds the details of the potential price periods.
 	Prices PotentialPricePeriods
+
+	// The probability distribution of prices for each price period of this week.
+	Bands *PriceBands
+
+	// The chance that each known price was entered wrong, if this is the week that
+	// happens. Only set when predicting with an ErrorRate.
+	MistakeChances [values.PricePeriodCount]float64
+
+	// How likely the ticker's price for each known price period is if this is the
+	// week that happens, compared to the other prices the period could have had. 0 for
+	// periods without a known price.
+	PeriodLikelihoods [values.PricePeriodCount]float64
+
+	// The chance of this week before the ticker's prices are taken into account,
+	// before being normalized.
+	prior float64
+
+	// The product of the weights given to this week by the prediction's constraints.
+	// 1 when there are no constraints.
+	constraintWeight float64
 }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"runtime"
+	"sync"
+)
+
+// BatchResult is the prediction for one ticker of a batch.
+type BatchResult struct {
+	Ticker     *PriceTicker
+	Prediction *Prediction
+	// Set if the prediction could not be made. Prediction will be nil.
+	Err error
+}
+
+// PredictBatch makes a prediction for every ticker with the same options. Predictions
+// are made on a pool of workers that share the price periods they work out, so tickers
+// with the same purchase price and early prices only pay for them once.
+//
+// Results are returned in the same order as ``tickers``. A ticker that can't be
+// predicted has it's error set rather than stopping the rest.
+func PredictBatch(tickers []*PriceTicker, options PredictOptions) []*BatchResult {
+	results := make([]*BatchResult, len(tickers))
+	cache := newPeriodCache()
+
+	workerCount := runtime.GOMAXPROCS(0)
+	if workerCount > len(tickers) {
+		workerCount = len(tickers)
+	}
+
+	indexes := make(chan int)
+	wait := new(sync.WaitGroup)
+	wait.Add(workerCount)
+
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			defer wait.Done()
+			for index := range indexes {
+				predictor := &Predictor{
+					Ticker:         tickers[index],
+					PredictOptions: options,
+					periodCache:    cache,
+				}
+				prediction, err := predictor.Predict()
+				results[index] = &BatchResult{
+					Ticker:     tickers[index],
+					Prediction: prediction,
+					Err:        err,
+				}
+			}
+		}()
+	}
+
+	for index := range tickers {
+		indexes <- index
+	}
+	close(indexes)
+	wait.Wait()
+
+	return results
+}
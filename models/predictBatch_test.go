@@ -0,0 +1,150 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+func batchTestTickers(t *testing.T) []*PriceTicker {
+	var tickers []*PriceTicker
+
+	// Several tickers that share a purchase price and early prices, so they share
+	// periods in the cache.
+	for _, thirdPrice := range []int{0, 78, 120, 140, 82} {
+		ticker := NewTicker(100, UNKNOWN, 2)
+		ticker.Prices[0] = 86
+		ticker.Prices[1] = 82
+		ticker.Prices[2] = thirdPrice
+		tickers = append(tickers, ticker)
+	}
+
+	// An unknown purchase price, which shares the prices of the known ones.
+	unknown := NewTicker(0, FLUCTUATING, 1)
+	unknown.Prices[0] = 86
+	unknown.Prices[1] = 82
+	tickers = append(tickers, unknown)
+
+	// A ticker with an interval.
+	interval := NewTicker(95, BIGSPIKE, 3)
+	interval.Prices[0] = 85
+	if err := interval.SetRange(3, 100, 150); err != nil {
+		t.Fatal(err)
+	}
+	tickers = append(tickers, interval)
+
+	// An impossible ticker.
+	impossible := NewTicker(100, UNKNOWN, 1)
+	impossible.Prices[0] = 86
+	impossible.Prices[1] = 600
+	tickers = append(tickers, impossible)
+
+	// An empty ticker, and the first one again.
+	tickers = append(tickers, NewTicker(100, DECREASING, 0), tickers[0])
+
+	return tickers
+}
+
+func assertSamePrediction(
+	t *testing.T, expected *Prediction, actual *Prediction, index int,
+) {
+	assert := assert.New(t)
+
+	if expected == nil || actual == nil {
+		assert.Equal(expected == nil, actual == nil, "ticker %v", index)
+		return
+	}
+
+	assert.Equal(expected.Heat, actual.Heat, "ticker %v", index)
+	assert.Equal(expected.MinPrice(), actual.MinPrice(), "ticker %v", index)
+	assert.Equal(expected.MaxPrice(), actual.MaxPrice(), "ticker %v", index)
+	assert.Equal(
+		expected.PurchasePrices.MinPrice(),
+		actual.PurchasePrices.MinPrice(),
+		"ticker %v",
+		index,
+	)
+
+	if !assert.Len(actual.Patterns, len(expected.Patterns), "ticker %v", index) {
+		return
+	}
+	for i, expectedPattern := range expected.Patterns {
+		actualPattern := actual.Patterns[i]
+		assert.Equal(expectedPattern.Chance(), actualPattern.Chance(), "ticker %v", index)
+		if !assert.Len(
+			actualPattern.PotentialWeeks,
+			len(expectedPattern.PotentialWeeks),
+			"ticker %v, pattern %v",
+			index,
+			expectedPattern.Pattern,
+		) {
+			continue
+		}
+
+		for j, expectedWeek := range expectedPattern.PotentialWeeks {
+			actualWeek := actualPattern.PotentialWeeks[j]
+			assert.Equal(expectedWeek.Chance(), actualWeek.Chance())
+			for period, expectedPeriod := range expectedWeek.Prices {
+				actualPeriod := actualWeek.Prices[period]
+				assert.Equal(expectedPeriod.MinPrice(), actualPeriod.MinPrice())
+				assert.Equal(expectedPeriod.MaxPrice(), actualPeriod.MaxPrice())
+				assert.Equal(
+					expectedPeriod.PatternPhase.Name(), actualPeriod.PatternPhase.Name(),
+				)
+			}
+		}
+	}
+}
+
+func TestPredictBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	tickers := batchTestTickers(t)
+	results := PredictBatch(tickers, PredictOptions{})
+	if !assert.Len(results, len(tickers)) {
+		t.FailNow()
+	}
+
+	for i, result := range results {
+		assert.Same(tickers[i], result.Ticker)
+
+		expected, expectedErr := (&Predictor{Ticker: tickers[i]}).Predict()
+		assert.Equal(expectedErr, result.Err, "ticker %v", i)
+		assertSamePrediction(t, expected, result.Prediction, i)
+	}
+
+	assert.True(xerrors.Is(results[7].Err, errs.ErrImpossibleTickerPrices))
+	assert.Nil(results[7].Prediction)
+}
+
+func TestPredictBatchEmpty(t *testing.T) {
+	assert := assert.New(t)
+	assert.Empty(PredictBatch(nil, PredictOptions{}))
+}
+
+// A period taken from the cache should belong to the phase of the week that asked for
+// it, not the week that made it.
+func TestPredictBatchPeriodPhases(t *testing.T) {
+	assert := assert.New(t)
+
+	tickers := batchTestTickers(t)
+	for _, result := range PredictBatch(tickers, PredictOptions{}) {
+		if result.Prediction == nil {
+			continue
+		}
+		for _, pattern := range result.Prediction.Patterns {
+			for _, week := range pattern.PotentialWeeks {
+				for _, period := range week.Prices {
+					phaseTicker := period.PatternPhase.(*patternPhaseAuto).Ticker()
+					assert.Equal(week.PurchasePrice, phaseTicker.PurchasePrice)
+					assert.Equal(result.Ticker.Prices, phaseTicker.Prices)
+				}
+			}
+		}
+	}
+}
+
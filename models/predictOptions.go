@@ -0,0 +1,116 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"golang.org/x/xerrors"
+	"math"
+)
+
+// TransitionMatrix is the chance of each in-game pattern occurring, where the row is
+// last week's pattern (including UNKNOWN) and the column is this week's pattern.
+type TransitionMatrix [len(PATTERNS)][len(PATTERNSGAME)]float64
+
+// Returns the transition matrix used by the game. See initialChanceMatrix for how the
+// UNKNOWN row is worked out.
+func DefaultTransitionMatrix() *TransitionMatrix {
+	matrix := TransitionMatrix(initialChanceMatrix)
+	return &matrix
+}
+
+// PredictOptions changes how a prediction is made. The zero value makes predictions
+// with the in-game patterns and chances.
+type PredictOptions struct {
+	// Scores the investment heat of the prediction. DefaultHeatScorer() is used if
+	// nil.
+	HeatScorer HeatScorer
+
+	// The patterns to consider. The in-game patterns are used if nil.
+	Patterns *PatternRegistry
+
+	// Replaces the game's chance of each in-game pattern given last week's pattern.
+	// Patterns from a custom registry that are not in-game patterns keep the chances
+	// from their definitions.
+	Transitions *TransitionMatrix
+
+	// Sets the chance of each pattern directly, ignoring last week's pattern. Chances
+	// are relative weights and do not need to add up to 1. Patterns missing from the
+	// map have no chance of occurring. Takes precedence over Transitions.
+	Priors map[PricePattern]float64
+
+	// Conditions the prediction on things we know about the week that are not
+	// prices. Applied to every potential week in order.
+	Constraints []Constraint
+
+	// The chance from 0.0 to 1.0 that any one price in the ticker was entered wrong.
+	// When set, a price that does not fit a potential week lowers the chance of the
+	// week instead of removing it, and the prediction reports the chance that each
+	// price is a mistake. A mistaken price is treated as equally likely to be any
+	// price the game can generate. Prices over what the game can make are allowed
+	// when this is set. 0 treats every price as correct, and the chances of a
+	// prediction approach the ones made with 0 as this gets smaller.
+	ErrorRate float64
+}
+
+// Returns the chance of a pattern before taking the ticker's prices into account.
+func (options *PredictOptions) baseChance(
+	definition *PatternDefinition, previous PricePattern,
+) float64 {
+	if options.Priors != nil {
+		return options.Priors[definition.Pattern]
+	}
+
+	pattern := definition.Pattern
+	if options.Transitions != nil &&
+		pattern >= 0 && int(pattern) < len(PATTERNSGAME) &&
+		previous >= 0 && int(previous) < len(PATTERNS) {
+		return options.Transitions[previous][pattern]
+	}
+
+	return definition.BaseChance(previous)
+}
+
+// Returns the pattern registry to use for predictions.
+func (options *PredictOptions) patterns() *PatternRegistry {
+	if options.Patterns == nil {
+		return defaultPatterns
+	}
+	return options.Patterns
+}
+
+func (options *PredictOptions) validate() error {
+	// Checks are written so that NaN values fail them.
+	if !(options.ErrorRate >= 0 && options.ErrorRate < 1) {
+		return xerrors.Errorf(
+			"error rate %v: %w", options.ErrorRate, errs.ErrInvalidErrorRate,
+		)
+	}
+	for pattern, chance := range options.Priors {
+		if err := validatePatternChance(chance); err != nil {
+			return xerrors.Errorf("prior for pattern %v: %w", int(pattern), err)
+		}
+	}
+	if options.Transitions != nil {
+		for previous, row := range options.Transitions {
+			for pattern, chance := range row {
+				if err := validatePatternChance(chance); err != nil {
+					return xerrors.Errorf(
+						"transition from pattern %v to %v: %w", previous, pattern, err,
+					)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Chances are normalized by their total, so a single infinite or NaN chance would
+// make every chance NaN.
+func validatePatternChance(chance float64) error {
+	if math.IsInf(chance, 0) || math.IsNaN(chance) {
+		return errs.ErrNonFinitePatternChance
+	}
+	if chance < 0 {
+		return errs.ErrNegativePatternChance
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package models
 
-import "github.com/peake100/turnup-go/errs"
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/values"
+)
 
 // Holds the potential pattern information for a prediction.
 type Patterns []*PotentialPattern
@@ -17,10 +20,26 @@ func (patterns Patterns) Get(pattern PricePattern) (*PotentialPattern, error) {
 	return nil, errs.ErrPatternStringValue
 }
 
+// Prediction is the result of a prediction. Nothing in a prediction changes once
+// Predict returns, so it is safe to read from many goroutines at once.
 type Prediction struct {
 	PriceSeries
 	Heat     int
 	Future   PriceSeries
 	Spikes   *SpikeChancesAll
 	Patterns Patterns
+	// Explains how Heat was reached.
+	HeatBreakdown *HeatBreakdown
+	// The probability distribution of prices over every potential week, weighted by
+	// the chance of each week.
+	Bands *PriceBands
+	// The chance that each known price in the ticker was entered wrong. Only set when
+	// predicting with an ErrorRate.
+	MistakeChances [values.PricePeriodCount]float64
+	// The chance of each purchase price Daisy Mae could have offered, given the
+	// ticker's prices. Certain if the ticker has a purchase price.
+	PurchasePrices *PriceDistribution
+
+	// A copy of the ticker the prediction was made from, for explanations.
+	ticker PriceTicker
 }
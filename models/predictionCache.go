@@ -0,0 +1,138 @@
+package models
+
+import (
+	"container/list"
+	"github.com/peake100/turnup-go/errs"
+	"golang.org/x/xerrors"
+	"sync"
+)
+
+// CacheStats counts how a PredictionCache has been used.
+type CacheStats struct {
+	// Predictions returned from the cache.
+	Hits uint64
+	// Predictions that had to be made.
+	Misses uint64
+	// Predictions dropped to make room for newer ones.
+	Evictions uint64
+	// The number of predictions in the cache.
+	Size int
+}
+
+// The chance of a prediction being served from the cache, from 0.0 to 1.0.
+func (stats CacheStats) HitRate() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+type cacheEntry struct {
+	key        string
+	prediction *Prediction
+	err        error
+}
+
+// PredictionCache stores predictions for tickers it has seen, so identical tickers
+// are only predicted once. When it is full, the least recently used prediction is
+// dropped. It is safe for concurrent use.
+//
+// Every prediction is made with the options the cache was made with. Use a different
+// cache for each set of options.
+//
+// Cached predictions are shared between every caller that asks for the same ticker,
+// and must not be modified.
+type PredictionCache struct {
+	options PredictOptions
+	size    int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	// Most recently used entries are at the front.
+	recent *list.List
+	stats  CacheStats
+}
+
+// Predict returns the prediction for ``ticker``, making it if it is not in the cache.
+// Tickers that can't be predicted are cached too, and return the same error.
+func (cache *PredictionCache) Predict(ticker *PriceTicker) (*Prediction, error) {
+	key := ticker.CanonicalKey()
+
+	cache.lock.Lock()
+	if element, ok := cache.entries[key]; ok {
+		cache.recent.MoveToFront(element)
+		cache.stats.Hits++
+		cache.lock.Unlock()
+
+		entry := element.Value.(*cacheEntry)
+		return entry.prediction, entry.err
+	}
+	cache.stats.Misses++
+	cache.lock.Unlock()
+
+	// We make the prediction without holding the lock so other tickers are not held
+	// up. If the same ticker is asked for at the same time, it may be predicted more
+	// than once.
+	predictor := &Predictor{
+		Ticker:         ticker,
+		PredictOptions: cache.options,
+	}
+	prediction, err := predictor.Predict()
+
+	cache.add(&cacheEntry{key: key, prediction: prediction, err: err})
+	return prediction, err
+}
+
+func (cache *PredictionCache) add(entry *cacheEntry) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	// Another caller may have added this ticker while we were predicting it.
+	if element, ok := cache.entries[entry.key]; ok {
+		element.Value = entry
+		cache.recent.MoveToFront(element)
+		return
+	}
+
+	cache.entries[entry.key] = cache.recent.PushFront(entry)
+	for cache.recent.Len() > cache.size {
+		oldest := cache.recent.Back()
+		cache.recent.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).key)
+		cache.stats.Evictions++
+	}
+	cache.stats.Size = cache.recent.Len()
+}
+
+// Stats returns how the cache has been used so far.
+func (cache *PredictionCache) Stats() CacheStats {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.stats
+}
+
+// Clear removes every prediction from the cache. Stats are kept.
+func (cache *PredictionCache) Clear() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	cache.entries = make(map[string]*list.Element, cache.size)
+	cache.recent.Init()
+	cache.stats.Size = 0
+}
+
+// NewPredictionCache makes a cache that holds up to ``size`` predictions made with
+// ``options``.
+func NewPredictionCache(size int, options PredictOptions) (*PredictionCache, error) {
+	if size < 1 {
+		return nil, xerrors.Errorf("size %v: %w", size, errs.ErrInvalidCacheSize)
+	}
+
+	return &PredictionCache{
+		options: options,
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		recent:  list.New(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"sync"
+	"testing"
+)
+
+func TestPredictionCache(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, err := NewPredictionCache(2, PredictOptions{})
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	first, err := cache.Predict(NewTicker(100, UNKNOWN, 0))
+	assert.NoError(err)
+	assert.Equal(CacheStats{Misses: 1, Size: 1}, cache.Stats())
+
+	// An identical ticker gets the same prediction.
+	again, err := cache.Predict(NewTicker(100, UNKNOWN, 0))
+	assert.NoError(err)
+	assert.Same(first, again)
+	assert.Equal(CacheStats{Hits: 1, Misses: 1, Size: 1}, cache.Stats())
+	assert.Equal(0.5, cache.Stats().HitRate())
+
+	// Impossible tickers are cached too.
+	impossible := NewTicker(100, UNKNOWN, 0)
+	impossible.Prices[0] = 600
+	for i := 0; i < 2; i++ {
+		prediction, err := cache.Predict(impossible)
+		assert.Nil(prediction)
+		assert.True(xerrors.Is(err, errs.ErrImpossibleTickerPrices))
+	}
+	assert.Equal(CacheStats{Hits: 2, Misses: 2, Size: 2}, cache.Stats())
+
+	// The empty ticker was used least recently, so it makes room for this one.
+	_, err = cache.Predict(NewTicker(90, UNKNOWN, 0))
+	assert.NoError(err)
+	assert.Equal(uint64(1), cache.Stats().Evictions)
+
+	_, err = cache.Predict(impossible)
+	assert.Error(err)
+	assert.Equal(uint64(3), cache.Stats().Hits)
+
+	evicted, err := cache.Predict(NewTicker(100, UNKNOWN, 0))
+	assert.NoError(err)
+	assert.NotSame(first, evicted)
+
+	cache.Clear()
+	assert.Equal(0, cache.Stats().Size)
+	assert.Equal(uint64(3), cache.Stats().Hits)
+}
+
+func TestPredictionCacheOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, err := NewPredictionCache(
+		10, PredictOptions{Priors: map[PricePattern]float64{DECREASING: 1}},
+	)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	prediction, err := cache.Predict(NewTicker(100, UNKNOWN, 0))
+	assert.NoError(err)
+	assert.Equal(1.0, prediction.Patterns[DECREASING].Chance())
+}
+
+func TestPredictionCacheInvalidSize(t *testing.T) {
+	assert := assert.New(t)
+
+	cache, err := NewPredictionCache(0, PredictOptions{})
+	assert.Nil(cache)
+	assert.True(xerrors.Is(err, errs.ErrInvalidCacheSize))
+}
+
+// Cached predictions are shared, so reading them from many goroutines must be safe.
+// Run with -race.
+func TestPredictionCacheConcurrent(t *testing.T) {
+	cache, err := NewPredictionCache(3, PredictOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wait := new(sync.WaitGroup)
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			ticker := NewTicker(100, UNKNOWN, 1)
+			ticker.Prices[0] = 85 + i%4
+
+			prediction, err := cache.Predict(ticker)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			prediction.MaxPeriods()
+			for _, pattern := range prediction.Patterns {
+				pattern.Future.MinPeriods()
+				for _, week := range pattern.PotentialWeeks {
+					week.GuaranteedPeriods()
+				}
+			}
+		}(i)
+	}
+	wait.Wait()
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(8), stats.Hits+stats.Misses)
+	assert.Equal(t, 3, stats.Size)
+}
@@ -0,0 +1,215 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+// These tests read finished predictions from many goroutines at once. They only prove
+// anything when run with the race detector:
+//
+//	go test -race ./models -run Race
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+const raceReaders = 8
+
+// Sums up a price series so readers can check they all saw the same thing.
+func readPriceSeries(series *PriceSeries) (total float64) {
+	total += float64(series.MinPrice() + series.GuaranteedPrice() + series.MaxPrice())
+	for _, periods := range [][]PricePeriod{
+		series.MinPeriods(), series.GuaranteedPeriods(), series.MaxPeriods(),
+	} {
+		for _, period := range periods {
+			total += float64(period)
+		}
+	}
+	return total
+}
+
+func readDistribution(dist *PriceDistribution) (total float64) {
+	if dist == nil || dist.IsEmpty() {
+		return 0
+	}
+	band := dist.Band()
+	return float64(dist.MinPrice()+dist.MaxPrice()+band.P10+band.P50+band.P90) +
+		band.Expected + dist.ChanceAtLeast(150) + dist.Chance(dist.Quantile(0.5))
+}
+
+func readBands(bands *PriceBands) (total float64) {
+	for period := PricePeriod(0); period < 12; period++ {
+		total += readDistribution(bands.Period(period))
+	}
+	return total + readDistribution(bands.WeeklyMax()) +
+		readDistribution(bands.FutureMax())
+}
+
+// Reads the periods of a week, and works each of them out again from the phase that
+// made it. Phases can be shared between weeks, so this works out the same phase from
+// many goroutines.
+func readWeek(week *PotentialWeek) (total float64) {
+	total += week.Chance() + readPriceSeries(&week.PriceSeries) +
+		readPriceSeries(&week.Future) + readBands(week.Bands) +
+		week.ChanceAtLeast(200) + week.ChanceAtLeastBy(120, 8)
+
+	subPeriod := 0
+	var lastPhase PatternPhase
+	for _, period := range week.Prices {
+		if period.PatternPhase != lastPhase {
+			subPeriod = 0
+			lastPhase = period.PatternPhase
+		}
+		again := period.PatternPhase.PotentialPeriod(period.PricePeriod, subPeriod)
+		total += float64(again.MinPrice()+again.MaxPrice()) + again.maxChance
+		total += float64(len(period.PatternPhase.Name()))
+		if period.Spikes.Any().Has() {
+			total++
+		}
+		subPeriod++
+	}
+	return total
+}
+
+// Reads every exported value of a prediction.
+func readPrediction(prediction *Prediction) (total float64) {
+	total += float64(prediction.Heat) + prediction.HeatBreakdown.Total +
+		readPriceSeries(&prediction.PriceSeries) +
+		readPriceSeries(&prediction.Future) +
+		readBands(prediction.Bands) +
+		readDistribution(prediction.PurchasePrices) +
+		prediction.ChanceAtLeast(200) +
+		prediction.ChanceAtLeastInPeriod(120, 5) +
+		prediction.ChanceAtLeastBy(150, 9)
+
+	for _, spike := range []HasSpikeChance{
+		prediction.Spikes.Any(), prediction.Spikes.Big(), prediction.Spikes.Small(),
+	} {
+		total += spike.Chance() + spike.Breakdown()[4] + spike.PeakBreakdown()[6] +
+			readDistribution(spike.PeakPrices())
+	}
+
+	for _, pattern := range prediction.Patterns {
+		total += pattern.Chance() + readPriceSeries(&pattern.PriceSeries) +
+			readPriceSeries(&pattern.Future) + readBands(pattern.Bands) +
+			pattern.ChanceAtLeast(200)
+		for _, week := range pattern.PotentialWeeks {
+			total += readWeek(week)
+		}
+	}
+	return total
+}
+
+// Reads a prediction from many goroutines at once, and checks they all saw the same
+// values as a single reader. The single reader goes last, so the goroutines are the
+// first to read anything.
+func assertConcurrentReads(t *testing.T, prediction *Prediction, name string) {
+	totals := make([]float64, raceReaders)
+	wait := new(sync.WaitGroup)
+	for i := 0; i < raceReaders; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			totals[i] = readPrediction(prediction)
+		}(i)
+	}
+	wait.Wait()
+
+	expected := readPrediction(prediction)
+	for _, total := range totals {
+		assert.Equal(t, expected, total, name)
+	}
+}
+
+func TestRaceConcurrentReaders(t *testing.T) {
+	for _, fixture := range benchmarkFixtures() {
+		if testing.Short() && fixture.Name == "UnknownPurchase" {
+			continue
+		}
+
+		prediction, err := (&Predictor{Ticker: fixture.Ticker}).Predict()
+		if !assert.NoError(t, err, fixture.Name) {
+			continue
+		}
+		assertConcurrentReads(t, prediction, fixture.Name)
+	}
+}
+
+func TestRaceConcurrentReadersErrorRate(t *testing.T) {
+	ticker := NewTicker(100, UNKNOWN, 3)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+	ticker.Prices[2] = 300
+	ticker.Prices[3] = 74
+
+	predictor := &Predictor{
+		Ticker:         ticker,
+		PredictOptions: PredictOptions{ErrorRate: 0.05},
+	}
+	prediction, err := predictor.Predict()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assertConcurrentReads(t, prediction, "error rate")
+}
+
+// Predictions made in a batch share periods with each other, so reading one while
+// the others are read should be safe too.
+func TestRaceConcurrentReadersBatch(t *testing.T) {
+	tickers := make([]*PriceTicker, 0, len(benchmarkFixtures()))
+	for _, fixture := range benchmarkFixtures() {
+		if fixture.Name == "UnknownPurchase" {
+			continue
+		}
+		tickers = append(tickers, fixture.Ticker)
+	}
+
+	wait := new(sync.WaitGroup)
+	for _, result := range PredictBatch(tickers, PredictOptions{}) {
+		if !assert.NoError(t, result.Err) {
+			continue
+		}
+		wait.Add(1)
+		go func(result *BatchResult) {
+			defer wait.Done()
+			assertConcurrentReads(t, result.Prediction, "batch")
+		}(result)
+	}
+	wait.Wait()
+}
+
+// Every period is worked out before a prediction is returned, so reading one later
+// never changes the phase it came from.
+func TestPredictionPhasesFrozen(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := (&Predictor{Ticker: NewTicker(100, UNKNOWN, 0)}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			subPeriod := 0
+			var lastPhase PatternPhase
+			for _, period := range week.Prices {
+				if period.PatternPhase != lastPhase {
+					subPeriod = 0
+					lastPhase = period.PatternPhase
+				}
+
+				phase := period.PatternPhase.(*patternPhaseAuto)
+				if !assert.True(phase.frozen, "phase frozen") {
+					t.FailNow()
+				}
+
+				before := *phase
+				again := phase.PotentialPeriod(period.PricePeriod, subPeriod)
+				assert.Equal(period.pricesVal, again.pricesVal, period.PricePeriod)
+				assert.Equal(before, *phase, "phase unchanged")
+				subPeriod++
+			}
+		}
+	}
+}
@@ -38,12 +38,18 @@ type HasSpikeChance interface {
 	HasSpikeRange
 	Chance() float64
 	Breakdown() *SpikeChanceBreakdown
+	PeakBreakdown() *SpikeChanceBreakdown
+	PeakPrices() *PriceDistribution
 }
 
 type SpikeChance struct {
 	SpikeRange
 	chance    float64
 	breakdown *SpikeChanceBreakdown
+
+	peakBreakdown *SpikeChanceBreakdown
+	peakPrices    *PriceDistribution
+	peakMixer     distributionMixer
 }
 
 func (spike *SpikeChance) Chance() float64 {
@@ -54,6 +60,26 @@ func (spike *SpikeChance) Breakdown() *SpikeChanceBreakdown {
 	return spike.breakdown
 }
 
+// The chance that the spike reaches its highest price on each price period. Unlike
+// Breakdown(), which counts every period a spike covers, each week only contributes
+// to a single period, so the breakdown adds up to Chance().
+func (spike *SpikeChance) PeakBreakdown() *SpikeChanceBreakdown {
+	return spike.peakBreakdown
+}
+
+// The distribution of the highest price reached by the spike, assuming the spike
+// occurs. Will be empty if no spike is possible.
+func (spike *SpikeChance) PeakPrices() *PriceDistribution {
+	return spike.peakPrices
+}
+
+func (spike *SpikeChance) updatePeak(
+	period PricePeriod, prices *PriceDistribution, weekChance float64,
+) {
+	spike.peakBreakdown[period] += weekChance
+	spike.peakMixer.add(prices, weekChance)
+}
+
 func (spike *SpikeChance) updatePeriodDensity(
 	update HasSpikeRange,
 	period PricePeriod,
@@ -101,7 +127,7 @@ func (spikes *SpikeChancesAll) updateRanges(info *SpikeRangeAll) {
 
 // We will updatePrices the density from the potential weeks.
 func (spikes *SpikeChancesAll) updateDensities(
-	updateWeek *PotentialWeek,
+	updateWeek *PotentialWeek, weekChance float64,
 ) {
 	// The idea behind this heatmap is simple: take the bin width of a given potential
 	// week, and add it to a running tally of each price period a spike occurs on that
@@ -110,7 +136,6 @@ func (spikes *SpikeChancesAll) updateDensities(
 
 	// If there is no spike, abort.
 	update := updateWeek.Spikes
-	weekChance := updateWeek.Chance()
 
 	if !update.any.Has() {
 		return
@@ -130,4 +155,37 @@ func (spikes *SpikeChancesAll) updateDensities(
 			spikes.small.updatePeriodDensity(update.small, period, weekChance)
 		}
 	}
+
+	spikes.updatePeaks(updateWeek, weekChance)
+}
+
+// Adds the peak timing and peak price of a normalized potential week to the peak
+// breakdowns.
+func (spikes *SpikeChancesAll) updatePeaks(
+	updateWeek *PotentialWeek, weekChance float64,
+) {
+
+	for _, potentialPeriod := range updateWeek.Prices {
+		if !potentialPeriod.Spikes.IsPeak() {
+			continue
+		}
+
+		period := potentialPeriod.PricePeriod
+		prices := updateWeek.Bands.Period(period)
+
+		spikes.any.updatePeak(period, prices, weekChance)
+		if potentialPeriod.Spikes.Big().Has() {
+			spikes.big.updatePeak(period, prices, weekChance)
+		} else {
+			spikes.small.updatePeak(period, prices, weekChance)
+		}
+	}
+}
+
+// Builds the peak price distributions once every week has been added.
+func (spikes *SpikeChancesAll) finalizePeaks() {
+	for _, spike := range []*SpikeChance{spikes.any, spikes.big, spikes.small} {
+		spike.peakPrices = spike.peakMixer.distribution()
+		spike.peakMixer = distributionMixer{}
+	}
 }
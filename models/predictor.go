@@ -8,18 +8,52 @@ type Predictor struct {
 	// The price ticker to use for this prediction
 	Ticker *PriceTicker
 
+	// Options for how the prediction is made
+	PredictOptions
+
 	// The prediction result
 	result *Prediction
 
 	// The total probability width
 	totalWidth float64
+
+	// Adds up the chance of each purchase price over every potential week
+	purchaseMixer distributionMixer
+
+	// Periods shared with other predictions in a batch. nil outside of a batch.
+	periodCache *periodCache
 }
 
 func (predictor *Predictor) increaseBinWidth(amount float64) {
 	predictor.totalWidth += amount
 }
 
+// Returns the chance of a pattern given the ticker's previous pattern, before taking
+// the ticker's prices into account.
+func (predictor *Predictor) baseChance(definition *PatternDefinition) float64 {
+	return predictor.PredictOptions.baseChance(
+		definition, predictor.Ticker.PreviousPattern,
+	)
+}
+
 func (predictor *Predictor) Predict() (*Prediction, error) {
+	if err := predictor.predictPatterns(); err != nil {
+		return nil, err
+	}
+	predictor.calculateChances(predictor.result)
+	predictor.calculateBands()
+	predictor.CalcHeat()
+
+	return predictor.result, nil
+}
+
+// Works out the potential weeks of every pattern, before their chances are
+// calculated.
+func (predictor *Predictor) predictPatterns() error {
+	if predictor.Ticker == nil {
+		return errs.ErrInvalidTicker
+	}
+
 	result := &Prediction{
 		Future: PriceSeries{
 			future:        true,
@@ -27,26 +61,53 @@ func (predictor *Predictor) Predict() (*Prediction, error) {
 		},
 		Spikes: &SpikeChancesAll{
 			small: &SpikeChance{
-				breakdown: new(SpikeChanceBreakdown),
+				breakdown:     new(SpikeChanceBreakdown),
+				peakBreakdown: new(SpikeChanceBreakdown),
 			},
 			big: &SpikeChance{
-				breakdown: new(SpikeChanceBreakdown),
+				breakdown:     new(SpikeChanceBreakdown),
+				peakBreakdown: new(SpikeChanceBreakdown),
 			},
 			any: &SpikeChance{
-				breakdown: new(SpikeChanceBreakdown),
+				breakdown:     new(SpikeChanceBreakdown),
+				peakBreakdown: new(SpikeChanceBreakdown),
 			},
 		},
 		Patterns: nil,
+		ticker:   *predictor.Ticker,
 	}
 	predictor.result = result
 
-	currentWeek := predictor.Ticker
+	// A price the game can't make is just a very obvious mistake if prices can be
+	// mistakes.
+	if err := predictor.Ticker.validate(predictor.ErrorRate > 0); err != nil {
+		return err
+	}
+	if err := predictor.PredictOptions.validate(); err != nil {
+		return err
+	}
+
+	definitions := predictor.PredictOptions.patterns().Definitions()
+	hasChance := false
+	for _, definition := range definitions {
+		if predictor.baseChance(definition) > 0 {
+			hasChance = true
+		}
+	}
+	if !hasChance {
+		return errs.ErrNoPatternChance
+	}
 
 	validPrices := false
-	for _, pattern := range PATTERNSGAME {
+	excludedWeeks := 0
+	for _, definition := range definitions {
 		patternPredictor := &patternPredictor{
-			Ticker:  predictor.Ticker,
-			Pattern: pattern,
+			Ticker:     predictor.Ticker,
+			Definition: definition,
+			BaseChance: predictor.baseChance(definition),
+			Options:    &predictor.PredictOptions,
+
+			periodCache: predictor.periodCache,
 		}
 
 		potentialPattern, binWidth := patternPredictor.Predict()
@@ -54,6 +115,7 @@ func (predictor *Predictor) Predict() (*Prediction, error) {
 		if len(potentialPattern.PotentialWeeks) > 0 {
 			validPrices = true
 		}
+		excludedWeeks += patternPredictor.excludedWeeks
 
 		// Integrate this data with our top-level summary
 		result.Patterns = append(result.Patterns, potentialPattern)
@@ -64,11 +126,11 @@ func (predictor *Predictor) Predict() (*Prediction, error) {
 	}
 
 	// If there are no possible price patterns based on this ticker, return an error
+	if !validPrices && excludedWeeks > 0 {
+		return errs.ErrConstraintsExcludeAll
+	}
 	if !validPrices {
-		return nil, errs.ErrImpossibleTickerPrices
+		return errs.ErrImpossibleTickerPrices
 	}
-	predictor.calculateChances(currentWeek, result)
-	predictor.CalcHeat()
-
-	return result, nil
+	return nil
 }
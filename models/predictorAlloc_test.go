@@ -0,0 +1,122 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// Tickers for the allocation benchmarks, from the least to the most work.
+func allocBenchmarkTickers() map[string]*PriceTicker {
+	knownPrices := NewTicker(100, FLUCTUATING, 3)
+	knownPrices.Prices[0] = 86
+	knownPrices.Prices[1] = 82
+	knownPrices.Prices[2] = 78
+	knownPrices.Prices[3] = 120
+
+	unknownPurchase := NewTicker(0, UNKNOWN, 1)
+	unknownPurchase.Prices[0] = 86
+	unknownPurchase.Prices[1] = 82
+
+	return map[string]*PriceTicker{
+		"KnownPrices":     knownPrices,
+		"NoPrices":        NewTicker(100, UNKNOWN, 0),
+		"UnknownPurchase": unknownPurchase,
+	}
+}
+
+func BenchmarkPredictAllocs(b *testing.B) {
+	for name, ticker := range allocBenchmarkTickers() {
+		ticker := ticker
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := (&Predictor{Ticker: ticker}).Predict(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestPeriodSet(t *testing.T) {
+	assert := assert.New(t)
+
+	var set periodSet
+	assert.Equal([]PricePeriod{}, set.periods())
+
+	set.add(11)
+	set.add(0)
+	set.add(4)
+	set.add(4)
+	assert.True(set.has(4))
+	assert.False(set.has(5))
+	assert.Equal([]PricePeriod{0, 4, 11}, set.periods())
+}
+
+func TestPriceSeriesUpdateAllocs(t *testing.T) {
+	assert := assert.New(t)
+
+	period := &PotentialPricePeriod{
+		pricesVal: pricesVal{minPrice: 80, guaranteedPrice: 80, maxPrice: 120},
+	}
+	other := new(PriceSeries)
+	other.updatePriceRangeFromPrices(period, 2)
+
+	series := new(PriceSeries)
+	allocs := testing.AllocsPerRun(100, func() {
+		series.updatePriceRangeFromPrices(period, 3)
+		series.updatePriceRangeFromOther(other)
+	})
+	assert.Zero(allocs)
+	assert.Equal([]PricePeriod{2, 3}, series.MaxPeriods())
+}
+
+func TestPotentialPeriodAllocs(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 0)
+	phase := &patternPhaseAuto{phaseImplement: new(steadyDecrease)}
+	phase.SetTicker(ticker)
+	phase.SetLength(7)
+
+	// Setting the phase up allocates every period at once, after that working out a
+	// period should not allocate anything.
+	phase.PotentialPeriod(0, 0)
+	allocs := testing.AllocsPerRun(10, func() {
+		// Start the generator over so every period is worked out again.
+		phase.filledPeriods = 0
+		phase.pricePeriodGen = phasePeriodGenerator{
+			Ticker:        ticker,
+			PurchasePrice: ticker.PurchasePrice,
+			PhaseFull:     phase,
+		}
+		phase.pricePeriodGen.Setup()
+
+		for subPeriod := 0; subPeriod < 7; subPeriod++ {
+			phase.PotentialPeriod(PricePeriod(subPeriod), subPeriod)
+		}
+	})
+	assert.Zero(allocs)
+	assert.Equal(6, phase.pricePeriodGen.LastCompletedSubPeriod)
+}
+
+func TestSpikeFlagsShared(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Same(spikeFlags(false, false, false), spikeFlags(false, true, true))
+
+	big := spikeFlags(true, true, false)
+	assert.True(big.Any().Has())
+	assert.True(big.Big().Has())
+	assert.False(big.Small().Has())
+	assert.False(big.IsPeak())
+
+	smallPeak := spikeFlags(true, false, true)
+	assert.True(smallPeak.Any().Has())
+	assert.False(smallPeak.Big().Has())
+	assert.True(smallPeak.Small().Has())
+	assert.True(smallPeak.IsPeak())
+}
@@ -0,0 +1,16 @@
+package models
+
+// Rolls up the price bands of every potential week into pattern and prediction level
+// bands. Must be called after chances are calculated, as the week chances are used to
+// weight each week's prices.
+func (predictor *Predictor) calculateBands() {
+	prediction := predictor.result
+
+	var allWeeks []*PotentialWeek
+	for _, potentialPattern := range prediction.Patterns {
+		potentialPattern.Bands = mixWeekBands(potentialPattern.PotentialWeeks)
+		allWeeks = append(allWeeks, potentialPattern.PotentialWeeks...)
+	}
+
+	prediction.Bands = mixWeekBands(allWeeks)
+}
@@ -5,32 +5,48 @@ import (
 )
 
 // Converts the chance on an Analysis() method from chance width to absolute chance
-// with a precision of 4 digits (XX.XX%).
+// with a precision of 4 digits (XX.XX%). The unrounded chance is returned so that
+// running totals over many weeks do not accumulate the rounding error.
 func (predictor *Predictor) setChanceFromWidth(
 	item hasFullAnalysis, totalWidth float64,
-) {
-	chance := item.Chance() / totalWidth
+) (exact float64) {
+	exact = item.Chance() / totalWidth
 	// round to 4 digits (xx.xx%)
-	chance = math.Round(chance*10000) / 10000
+	chance := math.Round(exact*10000) / 10000
 	item.setChance(chance)
+	return exact
 }
 
 // If we are in a price pattern which is VANISHINGLY unlikely, to the point that the
 // effective chance is 0, we are going to base the likelihood of each pattern off it's
-// base chance and the number of permutations we can eliminate.
+// base chance and the number of permutations we can eliminate. Each remaining week
+// still counts for the weight the constraints gave it.
 func (predictor *Predictor) fallBackToPatternCount(
-	prediction *Prediction, ticker *PriceTicker,
+	prediction *Prediction,
 ) (totalWidth float64) {
+	// The constraint weights are only compared to each other. Tiny weights may be
+	// what got us here, so we scale them against the largest before adding them up.
+	maxWeight := 0.0
+	for _, potentialPattern := range prediction.Patterns {
+		for _, week := range potentialPattern.PotentialWeeks {
+			maxWeight = math.Max(maxWeight, week.constraintWeight)
+		}
+	}
+
 	for _, potentialPattern := range prediction.Patterns {
-		potentialMatches := len(potentialPattern.PotentialWeeks)
-		maxPermutations := potentialPattern.Pattern.PermutationCount()
+		potentialMatches := 0.0
+		for _, week := range potentialPattern.PotentialWeeks {
+			potentialMatches += week.constraintWeight / maxWeight
+		}
+		definition := potentialPattern.definition
+		maxPermutations := definition.PermutationCount()
 
 		// Pattern chance is the number of active possibilities / the number of
 		// eliminated possibilities, weighted by the base chance
 		patternChance :=
-			float64(potentialMatches) /
+			potentialMatches /
 				float64(maxPermutations) *
-				potentialPattern.Pattern.BaseChance(ticker.PreviousPattern)
+				predictor.baseChance(definition)
 
 		totalWidth += patternChance
 		potentialPattern.setChance(patternChance)
@@ -47,26 +63,43 @@ func (predictor *Predictor) updateSummariesWithPattern(
 	predictor.setChanceFromWidth(potentialPattern, predictor.totalWidth)
 	for _, week := range potentialPattern.PotentialWeeks {
 		// Set the chance for this week
-		predictor.setChanceFromWidth(week, predictor.totalWidth)
+		weekChance := predictor.setChanceFromWidth(week, predictor.totalWidth)
 		// Update the spike chance heatmap with the normalized week
-		predictor.result.Spikes.updateDensities(week)
+		predictor.result.Spikes.updateDensities(week, weekChance)
+		// Add this week's share of the chance each price is a mistake
+		for i, mistakeChance := range week.MistakeChances {
+			predictor.result.MistakeChances[i] += weekChance * mistakeChance
+		}
+		// Add this week's share of the chance for its purchase price
+		predictor.purchaseMixer.add(
+			newPointDistribution(week.PurchasePrice), weekChance,
+		)
 	}
 
 	// We want to use the big and small pattern chance as the spike chance so
 	// that they match. If we compute separately, then floating point errors cause
 	// the spike chances to mismatch the pattern chance, which is nonsensical.
-	if potentialPattern.Pattern == BIGSPIKE {
+	switch potentialPattern.Pattern {
+	case BIGSPIKE:
 		predictor.result.Spikes.big.chance = potentialPattern.Chance()
-	} else if potentialPattern.Pattern == SMALLSPIKE {
+	case SMALLSPIKE:
 		predictor.result.Spikes.small.chance = potentialPattern.Chance()
+	default:
+		// Patterns from a custom registry may have spikes of their own, so we add
+		// up the chance of their weeks that spike.
+		for _, week := range potentialPattern.PotentialWeeks {
+			if week.Spikes.Big().Has() {
+				predictor.result.Spikes.big.chance += week.Chance()
+			} else if week.Spikes.Small().Has() {
+				predictor.result.Spikes.small.chance += week.Chance()
+			}
+		}
 	}
 }
 
 // Calculate the chances of each price pattern permutation once they have been
 // calculated.
-func (predictor *Predictor) calculateChances(
-	ticker *PriceTicker, prediction *Prediction,
-) {
+func (predictor *Predictor) calculateChances(prediction *Prediction) {
 	// We are going to calculate the likelihood that a bell price in the ticker came
 	// from a given range by looping through the price periods we have data for and
 	// examining the likelihood that the results came from the one possible phase combo
@@ -102,7 +135,7 @@ func (predictor *Predictor) calculateChances(
 	// divided by the number of possible weeks.
 	totalWidth := predictor.totalWidth
 	if totalWidth <= 0 {
-		totalWidth = predictor.fallBackToPatternCount(prediction, ticker)
+		totalWidth = predictor.fallBackToPatternCount(prediction)
 		predictor.totalWidth = totalWidth
 	}
 
@@ -115,6 +148,10 @@ func (predictor *Predictor) calculateChances(
 
 	// Lastly, get the total spike chance
 	spikeInfo.any.chance = spikeInfo.Big().Chance() + spikeInfo.Small().Chance()
+	spikeInfo.finalizePeaks()
+
+	// And the chance of each purchase price Daisy Mae might have offered
+	prediction.PurchasePrices = predictor.purchaseMixer.distribution()
 
 	// And we're done! Phew!
 }
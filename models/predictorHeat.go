@@ -1,65 +1,141 @@
 package models
 
-import "math"
+import (
+	"github.com/peake100/turnup-go/values"
+	"math"
+)
 
-// Generates heat for a possible near-future spike.
-func heatSpikePeriod(
-	period PricePeriod,
-	breakdown *SpikeChanceBreakdown,
-	periodMultiplier float64,
-) (periodHeat float64) {
-	// If the period is beyond the end of the week, we add 0.
-	if int(period) < len(breakdown) {
-		periodHeat = breakdown[period] * periodMultiplier
+// HeatScorer scores how good of an investment an island is. The predictor calls Score
+// once chances have been calculated, and rounds the breakdown's total to get the
+// prediction's Heat.
+type HeatScorer interface {
+	// Returns the breakdown of the heat for a prediction. ``currentPeriod`` is the
+	// price period the island is currently in.
+	Score(prediction *Prediction, currentPeriod PricePeriod) *HeatBreakdown
+}
+
+// HeatBreakdown explains how a prediction's Heat was reached.
+type HeatBreakdown struct {
+	// The un-rounded heat. Prediction.Heat is this value rounded to the nearest int.
+	Total float64
+	// The heat contributed by each pattern. Adds up to Total.
+	Patterns []*PatternHeat
+}
+
+// PatternHeat is the heat contributed by a single pattern.
+type PatternHeat struct {
+	Pattern PricePattern
+	// The chance of this pattern.
+	Chance float64
+	// The weighted average of the future guaranteed and max price of this pattern.
+	PriceAverage float64
+	// PriceAverage weighted by the chance of this pattern.
+	BaseHeat float64
+	// Multiplier for near-future spikes. Always at least 1.
+	SpikeMultiplier float64
+	// How much each price period added to SpikeMultiplier.
+	PeriodBonus [values.PricePeriodCount]float64
+	// The final heat for this pattern: BaseHeat * SpikeMultiplier
+	Heat float64
+}
+
+// SpikeHeatScorer scores heat on the average of the future guaranteed and max price of
+// each pattern, weighted by the chance of that pattern. Spike patterns get a bonus for
+// the chance of a spike in the next few price periods.
+type SpikeHeatScorer struct {
+	// Weight of the future guaranteed price when averaging a pattern's prices.
+	GuaranteedPriceWeight float64
+	// Weight of the future max price when averaging a pattern's prices.
+	MaxPriceWeight float64
+	// Multipliers for the chance of a spike occurring in the current period, the next
+	// period, and so on. Periods past the end of the week are ignored.
+	SpikeWeights []float64
+}
+
+// Returns the heat scorer used when a predictor does not have one set.
+//
+// We want a current spike with a below average roll to mostly out-shine a 100%
+// possible potential spike the next period with a higher average. With this setup, a
+// pattern with a spike 20% below average will generate equal heat as a possible spike
+// with the same average and 100% possibility of happening the same day.
+func DefaultHeatScorer() *SpikeHeatScorer {
+	return &SpikeHeatScorer{
+		GuaranteedPriceWeight: 0.5,
+		MaxPriceWeight:        0.5,
+		SpikeWeights:          []float64{0.4, 0.2, 0.2},
 	}
-	return periodHeat
 }
 
 // Generate a heat multiplier based on the likelihood of a spike happening in the
-// next 3 periods starting with the current period.
-func heatSpikeMultiplier(
+// next few periods starting with the current period.
+func (scorer *SpikeHeatScorer) spikeMultiplier(
 	currentPeriod PricePeriod,
 	breakdown *SpikeChanceBreakdown,
-) (spikeMultiplier float64) {
-	nextPeriod := currentPeriod + 1
-	twoPeriods := currentPeriod + 2
-
+	patternHeat *PatternHeat,
+) {
 	// We need this to always increase the score, so start it at 1.
-	spikeMultiplier = 1
-
-	// Give bonuses for possible spikes spikes in the next 3 periods. We want a current
-	// spike with a below average roll to mostly out-shine a 100% possible potential
-	// spike the next period with a higher average. With this current setup, a pattern
-	// with a spike 20% below average will generate equal heat as a possible spike
-	// with the same average and 100% possibility of happening the same day.
-	spikeMultiplier += heatSpikePeriod(currentPeriod, breakdown, 0.4)
-	spikeMultiplier += heatSpikePeriod(nextPeriod, breakdown, 0.2)
-	spikeMultiplier += heatSpikePeriod(twoPeriods, breakdown, 0.2)
-	return spikeMultiplier
-}
+	patternHeat.SpikeMultiplier = 1
 
-// Calculate the investment heat for this island.
-func (predictor *Predictor) CalcHeat() {
-	prediction := predictor.result
+	for i, weight := range scorer.SpikeWeights {
+		period := currentPeriod + PricePeriod(i)
+		// If the period is beyond the end of the week, we add nothing.
+		if period < 0 || int(period) >= len(breakdown) {
+			continue
+		}
 
-	var heatFloat float64
-	currentPeriod := predictor.Ticker.CurrentPeriod
+		periodBonus := breakdown[period] * weight
+		patternHeat.PeriodBonus[period] += periodBonus
+		patternHeat.SpikeMultiplier += periodBonus
+	}
+}
+
+// Score the heat of a prediction.
+func (scorer *SpikeHeatScorer) Score(
+	prediction *Prediction, currentPeriod PricePeriod,
+) *HeatBreakdown {
+	breakdown := new(HeatBreakdown)
 
 	for _, pattern := range prediction.Patterns {
+		patternHeat := &PatternHeat{
+			Pattern:         pattern.Pattern,
+			Chance:          pattern.Chance(),
+			SpikeMultiplier: 1,
+		}
+
 		// The base heat for each pattern will be the avg of the max and guaranteed
 		// price times the chance of the pattern
-		priceAverage := pattern.Future.MaxPrice() + pattern.Future.GuaranteedPrice()
-		baseHeat := float64(priceAverage) / 2 * pattern.chance
+		patternHeat.PriceAverage = float64(pattern.Future.MaxPrice())*
+			scorer.MaxPriceWeight +
+			float64(pattern.Future.GuaranteedPrice())*scorer.GuaranteedPriceWeight
+		patternHeat.BaseHeat = patternHeat.PriceAverage * pattern.Chance()
+
 		switch pattern.Pattern {
 		case BIGSPIKE:
-			breakdown := prediction.Spikes.Big().Breakdown()
-			baseHeat *= heatSpikeMultiplier(currentPeriod, breakdown)
+			spikeBreakdown := prediction.Spikes.Big().Breakdown()
+			scorer.spikeMultiplier(currentPeriod, spikeBreakdown, patternHeat)
 		case SMALLSPIKE:
-			breakdown := prediction.Spikes.Small().Breakdown()
-			baseHeat *= heatSpikeMultiplier(currentPeriod, breakdown)
+			spikeBreakdown := prediction.Spikes.Small().Breakdown()
+			scorer.spikeMultiplier(currentPeriod, spikeBreakdown, patternHeat)
 		}
-		heatFloat += baseHeat
+
+		patternHeat.Heat = patternHeat.BaseHeat * patternHeat.SpikeMultiplier
+		breakdown.Total += patternHeat.Heat
+		breakdown.Patterns = append(breakdown.Patterns, patternHeat)
+	}
+
+	return breakdown
+}
+
+// Calculate the investment heat for this island.
+func (predictor *Predictor) CalcHeat() {
+	prediction := predictor.result
+
+	scorer := predictor.HeatScorer
+	if scorer == nil {
+		scorer = DefaultHeatScorer()
 	}
 
-	prediction.Heat = int(math.Round(heatFloat))
+	breakdown := scorer.Score(prediction, predictor.Ticker.CurrentPeriod)
+	prediction.HeatBreakdown = breakdown
+	prediction.Heat = int(math.Round(breakdown.Total))
 }
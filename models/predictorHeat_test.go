@@ -0,0 +1,129 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// A scorer that gives every pattern a flat heat of it's chance * 100
+type flatHeatScorer struct{}
+
+func (scorer flatHeatScorer) Score(
+	prediction *Prediction, _ PricePeriod,
+) *HeatBreakdown {
+	breakdown := new(HeatBreakdown)
+	for _, pattern := range prediction.Patterns {
+		heat := pattern.Chance() * 100
+		breakdown.Total += heat
+		breakdown.Patterns = append(
+			breakdown.Patterns, &PatternHeat{Pattern: pattern.Pattern, Heat: heat},
+		)
+	}
+	return breakdown
+}
+
+func newHeatTicker() *PriceTicker {
+	ticker := NewTicker(100, DECREASING, 1)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+	return ticker
+}
+
+func TestHeatDefaultBreakdown(t *testing.T) {
+	assert := assert.New(t)
+
+	predictor := &Predictor{Ticker: newHeatTicker()}
+	prediction, err := predictor.Predict()
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	breakdown := prediction.HeatBreakdown
+	assert.Equal(int(math.Round(breakdown.Total)), prediction.Heat, "heat rounded")
+	assert.Len(breakdown.Patterns, len(prediction.Patterns), "pattern count")
+
+	var total float64
+	for _, patternHeat := range breakdown.Patterns {
+		total += patternHeat.Heat
+
+		pattern, _ := prediction.Patterns.Get(patternHeat.Pattern)
+		assert.Equal(pattern.Chance(), patternHeat.Chance, "pattern chance")
+		assert.Equal(
+			patternHeat.BaseHeat*patternHeat.SpikeMultiplier,
+			patternHeat.Heat,
+			"pattern heat",
+		)
+
+		bonusTotal := 1.0
+		for _, bonus := range patternHeat.PeriodBonus {
+			bonusTotal += bonus
+		}
+		assert.InDelta(
+			patternHeat.SpikeMultiplier, bonusTotal, 0.000001, "period bonus total",
+		)
+
+		switch patternHeat.Pattern {
+		case BIGSPIKE, SMALLSPIKE:
+			// Only the current period and the 2 after it get a bonus.
+			for period, bonus := range patternHeat.PeriodBonus {
+				if period < 1 || period > 3 {
+					assert.Equal(0.0, bonus, "bonus for period %v", period)
+				}
+			}
+		default:
+			assert.Equal(1.0, patternHeat.SpikeMultiplier, "no spike bonus")
+		}
+	}
+	assert.InDelta(breakdown.Total, total, 0.000001, "pattern heat total")
+
+	// The decreasing pattern has a guaranteed future price of 82 and a max of 82
+	decreasing := breakdown.Patterns[DECREASING]
+	assert.Equal(82.0, decreasing.PriceAverage, "decreasing average")
+}
+
+func TestHeatCustomScorer(t *testing.T) {
+	assert := assert.New(t)
+
+	predictor := &Predictor{
+		Ticker:         newHeatTicker(),
+		PredictOptions: PredictOptions{HeatScorer: flatHeatScorer{}},
+	}
+	prediction, err := predictor.Predict()
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	assert.Equal(100, prediction.Heat, "flat heat")
+}
+
+func TestHeatCustomWeights(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultPrediction, err := (&Predictor{Ticker: newHeatTicker()}).Predict()
+	if !assert.NoError(err, "predict default") {
+		t.FailNow()
+	}
+
+	// Only weigh the max price, and ignore spikes entirely.
+	scorer := &SpikeHeatScorer{MaxPriceWeight: 1}
+	predictor := &Predictor{
+		Ticker:         newHeatTicker(),
+		PredictOptions: PredictOptions{HeatScorer: scorer},
+	}
+	prediction, err := predictor.Predict()
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	var expected float64
+	for _, pattern := range prediction.Patterns {
+		expected += float64(pattern.Future.MaxPrice()) * pattern.Chance()
+	}
+
+	assert.Equal(int(math.Round(expected)), prediction.Heat, "max price heat")
+	assert.NotEqual(defaultPrediction.Heat, prediction.Heat, "heat changed")
+}
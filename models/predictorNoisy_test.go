@@ -0,0 +1,174 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+// A decreasing week where wednesday morning was entered as 780 instead of 78.
+func newTypoTicker() *PriceTicker {
+	ticker := NewTicker(100, UNKNOWN, 5)
+	for i, price := range []int{86, 82, 780, 74, 70, 66} {
+		ticker.Prices[i] = price
+	}
+	return ticker
+}
+
+func TestNoisyTypo(t *testing.T) {
+	assert := assert.New(t)
+
+	// Without an error rate, the price is more than the game can make.
+	_, err := (&Predictor{Ticker: newTypoTicker()}).Predict()
+	assert.True(xerrors.Is(err, errs.ErrInvalidTickerPrice), "no error rate")
+
+	prediction, err := (&Predictor{
+		Ticker:         newTypoTicker(),
+		PredictOptions: PredictOptions{ErrorRate: 0.05},
+	}).Predict()
+	if !assert.NoError(err, "error rate") {
+		t.FailNow()
+	}
+
+	// The rest of the prices fit a decreasing week, or the steady decrease at the
+	// start of a spike week, about as well as they do without the typo.
+	withoutTypo := newTypoTicker()
+	withoutTypo.Prices[2] = 0
+	exact, err := (&Predictor{Ticker: withoutTypo}).Predict()
+	if !assert.NoError(err, "without typo") {
+		t.FailNow()
+	}
+	for _, pattern := range PATTERNSGAME {
+		assert.InDelta(
+			exact.Patterns[pattern].Chance(),
+			prediction.Patterns[pattern].Chance(),
+			0.01,
+			pattern.String(),
+		)
+	}
+	assert.Less(prediction.Patterns[FLUCTUATING].Chance(), 0.001)
+
+	assert.Greater(prediction.MistakeChances[2], 0.99, "typo")
+	for _, period := range []int{0, 1, 3, 4, 5} {
+		assert.Less(prediction.MistakeChances[period], 0.05, "period", period)
+	}
+	for period := 6; period < 12; period++ {
+		assert.Equal(0.0, prediction.MistakeChances[period], "unknown period", period)
+	}
+
+	// The band for the typo should reflect where the real price probably was.
+	assert.Less(prediction.Bands.Period(2).Quantile(0.5), 100)
+}
+
+func TestNoisyConsistentPrices(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 2)
+	for i, price := range []int{86, 82, 78} {
+		ticker.Prices[i] = price
+	}
+
+	noisy, err := (&Predictor{
+		Ticker:         ticker,
+		PredictOptions: PredictOptions{ErrorRate: 0.01},
+	}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// Every potential week is kept, but the ones that don't fit the prices are very
+	// unlikely.
+	fluctuating := noisy.Patterns[FLUCTUATING]
+	assert.Len(fluctuating.PotentialWeeks, FLUCTUATING.PermutationCount())
+	assert.Less(fluctuating.Chance(), 0.0001)
+
+	for period := 0; period < 3; period++ {
+		assert.Less(noisy.MistakeChances[period], 0.001)
+	}
+}
+
+// A vanishingly small error rate should predict the same chances as no error rate.
+func TestNoisyConvergesToExact(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 3)
+	for i, price := range []int{86, 82, 90, 140} {
+		ticker.Prices[i] = price
+	}
+
+	exact, err := (&Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err, "exact") {
+		t.FailNow()
+	}
+	noisy, err := (&Predictor{
+		Ticker:         ticker,
+		PredictOptions: PredictOptions{ErrorRate: 1e-12},
+	}).Predict()
+	if !assert.NoError(err, "noisy") {
+		t.FailNow()
+	}
+
+	for _, pattern := range PATTERNSGAME {
+		assert.InDelta(
+			exact.Patterns[pattern].Chance(),
+			noisy.Patterns[pattern].Chance(),
+			0.0001,
+			pattern.String(),
+		)
+	}
+	assert.InDelta(exact.Spikes.Big().Chance(), noisy.Spikes.Big().Chance(), 0.0001)
+	assert.InDelta(
+		exact.Spikes.Small().Chance(), noisy.Spikes.Small().Chance(), 0.0001,
+	)
+}
+
+func TestNoisyInvalidErrorRate(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1} {
+		_, err := (&Predictor{
+			Ticker:         NewTicker(100, UNKNOWN, 0),
+			PredictOptions: PredictOptions{ErrorRate: rate},
+		}).Predict()
+		assert.True(t, xerrors.Is(err, errs.ErrInvalidErrorRate), rate)
+	}
+}
+
+// Prices at the very bottom of a bracket are rounded using float32 math, which used
+// to get a bin width that was a hair under 0. That made the chance of a mistake more
+// than 1.
+func TestNoisyMistakeChancesBounded(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := (&Predictor{
+		Ticker:         fixtureTicker(t, "NearImpossible"),
+		PredictOptions: PredictOptions{ErrorRate: 0.01},
+	}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	for period, chance := range prediction.MistakeChances {
+		assert.LessOrEqual(chance, 1+1e-9, "period %v", period)
+	}
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			for period, chance := range week.MistakeChances {
+				assert.LessOrEqual(chance, 1+1e-9, "week period %v", period)
+			}
+		}
+	}
+}
+
+func TestMistakeRangeChance(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(1.0/660, mistakeRangeChance(100, 100))
+	assert.Equal(1.0/660, mistakeRangeChance(780, 780))
+	assert.Equal(460.0/660, mistakeRangeChance(201, 0))
+	assert.Equal(1.0/660, mistakeRangeChance(700, 0))
+	assert.Equal(70.0/660, mistakeRangeChance(0, 70))
+	assert.Equal(1.0, mistakeRangeChance(0, 1000))
+}
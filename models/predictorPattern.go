@@ -1,12 +1,26 @@
 package models
 
+// The lowest and highest purchase price Daisy Mae can offer on sunday.
+const (
+	minPurchasePrice = 90
+	maxPurchasePrice = 110
+)
+
 type patternPredictor struct {
 	// Info
-	Ticker  *PriceTicker
-	Pattern PricePattern
+	Ticker     *PriceTicker
+	Definition *PatternDefinition
+	// The chance of this pattern before taking the ticker's prices into account.
+	BaseChance float64
+	Options    *PredictOptions
+
+	// Periods shared with other predictions in a batch. May be nil.
+	periodCache *periodCache
 
 	// The total probability width of this pattern
 	binWidth float64
+	// The number of weeks removed by constraints
+	excludedWeeks int
 
 	result *PotentialPattern
 }
@@ -15,46 +29,32 @@ func (predictor *patternPredictor) increaseBinWidth(amount float64) {
 	predictor.binWidth += amount
 }
 
-// Makes a duplicate of the current phase pattern to be a new possibility
-func (predictor *patternPredictor) duplicatePhasePattern(
-	patternPhases []PatternPhase,
-) []PatternPhase {
-
-	dupedPhases := make([]PatternPhase, len(patternPhases))
-	for i, phase := range patternPhases {
-		var branchPhase PatternPhase
-
-		// If the phase is finalized, we don't need to copy it. It's values will not
-		// be changing.
-		if phase.IsFinal() {
-			branchPhase = phase
-		} else {
-			branchPhase = phase.Duplicate()
-		}
-
-		dupedPhases[i] = branchPhase
-	}
-	return dupedPhases
-}
-
 // Once a particular phase pattern is fully computed, this function build the potential
 // prices for each price period. Returns nil if this pattern is impossible given the
 // ticker's real-world values
 func (predictor *patternPredictor) addWeekFromFinalizedPhases(
-	patternPhases []PatternPhase,
+	ticker *PriceTicker, purchasePriceChance float64, patternPhases []PatternPhase,
 ) {
 	thisWeekPredictor := &weekPredictor{
-		Ticker:        predictor.Ticker,
-		Pattern:       predictor.Pattern,
+		Ticker:        ticker,
+		Definition:    predictor.Definition,
+		BaseChance:    predictor.BaseChance * purchasePriceChance,
 		PatternPhases: patternPhases,
+		Options:       predictor.Options,
 	}
 
 	potentialWeek, binWidth := thisWeekPredictor.Predict()
-	if potentialWeek == nil {
-		return
+	if thisWeekPredictor.excluded {
+		predictor.excludedWeeks++
 	}
 
 	result := predictor.result
+	if ruledOutBy := thisWeekPredictor.ruledOutBy; ruledOutBy != nil {
+		predictor.addRuledOutBy(ruledOutBy)
+	}
+	if potentialWeek == nil {
+		return
+	}
 
 	// Otherwise, add the result and updatePrices all of our pattern's stats
 	result.PotentialWeeks = append(result.PotentialWeeks, potentialWeek)
@@ -64,87 +64,33 @@ func (predictor *patternPredictor) addWeekFromFinalizedPhases(
 	predictor.increaseBinWidth(binWidth)
 }
 
-// Creates a new branch and calculates it's possible permutations
-func (predictor *patternPredictor) computeBranch(
-	thisPossibleLength int,
-	possibilityIndex int,
-	phaseIndex int,
-	allPossibleLengths []int,
-	patternPhases []PatternPhase,
-) {
-	var newBranch []PatternPhase
-	if possibilityIndex < len(allPossibleLengths)-1 {
-		// duplicate our current pattern so we can set the possible length
-		// for this phase
-		newBranch = predictor.duplicatePhasePattern(patternPhases)
-	} else {
-		// If this is the last possible length, we can just re-use the current
-		// branch rather than making a new duplicate and throwing away our
-		// current
-		newBranch = patternPhases
-	}
-
-	// set the branch phases' length to this possibility
-	newBranch[phaseIndex].SetLength(thisPossibleLength)
-	// Tell the work group we are adding a new branch we need to wait for
-	// Launch the branch
-	predictor.branchPhases(newBranch)
-}
-
-// Takes an array of pattern phases and recursively works through all un-computed
-// possible phase length patterns.
-func (predictor *patternPredictor) branchPhases(
-	patternPhases []PatternPhase,
-) {
-	// To figure out the pattern for a week, we need to find all the possible lengths
-	// for each phase, then make a copy of the phase pattern with that possibility
-	// set to be re-iterated over in a new routine. We continue until all possibilities
-	// in all goroutines have reported they are finalized.
-	//
-	// There is no variance in the price pattern of each phase, only in how long the
-	// phase lasts. So if we have all possible combinations of phase lengths, then we
-	// have all possible price patterns.
-	for phaseIndex, phase := range patternPhases {
-
-		// If this phase has it's final length set, we can continue to the next phase.
-		if phase.IsFinal() {
-			continue
+// Keeps the latest period a week was thrown out on. If every week is thrown out,
+// that's the price that ruled the pattern out.
+func (predictor *patternPredictor) addRuledOutBy(period *PotentialPricePeriod) {
+	result := predictor.result
+	if len(result.ruledOutBy) > 0 {
+		latest := result.ruledOutBy[0].PricePeriod
+		if period.PricePeriod < latest {
+			return
 		}
-
-		// Get all the possible lengths for this phase,
-		possibleLengths := phase.PossibleLengths(patternPhases)
-		// If the possibilities are nil, then this phase is waiting for more
-		// information, and we should continue to the next phase
-		if possibleLengths == nil {
-			continue
+		if period.PricePeriod > latest {
+			result.ruledOutBy = result.ruledOutBy[:0]
 		}
+	}
 
-		// Otherwise we need to create a new possible pattern branch for each phase
-		// length and branch off of it.
-		for i, phaseLength := range possibleLengths {
-			predictor.computeBranch(
-				phaseLength,
-				i,
-				phaseIndex,
-				possibleLengths,
-				patternPhases,
-			)
+	for _, existing := range result.ruledOutBy {
+		if existing.PatternPhase.Name() == period.PatternPhase.Name() {
+			return
 		}
-
-		// We don't have a complete branch if we are permutating possibilities, so
-		// return
-		return
 	}
-
-	// If we make it all the way through than we have hit a fully formed possible phase
-	// pattern! Now we can compute the possible prices and return them as the result
-	predictor.addWeekFromFinalizedPhases(patternPhases)
+	result.ruledOutBy = append(result.ruledOutBy, period)
 }
 
 func (predictor *patternPredictor) setup() {
 	predictor.result = &PotentialPattern{
-		Analysis: NewAnalysis(predictor.Ticker),
-		Pattern:  predictor.Pattern,
+		Analysis:   NewAnalysis(predictor.Ticker),
+		Pattern:    predictor.Definition.Pattern,
+		definition: predictor.Definition,
 		Spikes: &SpikeRangeAll{
 			big:   new(SpikeRange),
 			small: new(SpikeRange),
@@ -153,17 +99,91 @@ func (predictor *patternPredictor) setup() {
 	}
 }
 
+// Lets the phases of a progression look up their periods in the batch's period cache.
+func (predictor *patternPredictor) shareCache(patternPhases []PatternPhase) {
+	if predictor.periodCache == nil {
+		return
+	}
+	for i, phase := range patternPhases {
+		if autoPhase, ok := phase.(*patternPhaseAuto); ok {
+			autoPhase.cacheInfo = &phaseCacheInfo{
+				cache:      predictor.periodCache,
+				definition: predictor.Definition,
+				phaseIndex: i,
+			}
+		}
+	}
+}
+
+// Freezes every phase the finished pattern points to, so nothing about the pattern
+// changes once it is returned.
+func (predictor *patternPredictor) freezePhases() {
+	freeze := func(period *PotentialPricePeriod) {
+		if autoPhase, ok := period.PatternPhase.(*patternPhaseAuto); ok {
+			autoPhase.freeze()
+		}
+	}
+
+	for _, week := range predictor.result.PotentialWeeks {
+		for _, period := range week.Prices {
+			freeze(period)
+		}
+	}
+	for _, period := range predictor.result.ruledOutBy {
+		freeze(period)
+	}
+}
+
 // Calculate all the possible phase permutations for a given price pattern.
 func (predictor *patternPredictor) Predict() (
 	result *PotentialPattern, binWidth float64,
 ) {
 	predictor.setup()
 
-	// Get the base phase progression of this pattern
-	patternPhases := predictor.Pattern.PhaseProgression(predictor.Ticker)
-	predictor.branchPhases(patternPhases)
+	// A pattern that cannot follow last week's pattern has no possible weeks, so we
+	// can skip working out its phases.
+	if predictor.BaseChance <= 0 {
+		return predictor.result, 0
+	}
+
+	// If we don't know the purchase price, every price Daisy Mae might have offered
+	// is equally likely before we look at the ticker's prices. We work out the
+	// potential weeks for each one separately, so the chance of each purchase price is
+	// weighted by how well it fits the ticker just like the phase lengths are.
+	purchasePrices := []int{predictor.Ticker.PurchasePrice}
+	if predictor.Ticker.PurchasePrice == 0 {
+		purchasePrices = make([]int, 0, maxPurchasePrice-minPurchasePrice+1)
+		for price := minPurchasePrice; price <= maxPurchasePrice; price++ {
+			purchasePrices = append(purchasePrices, price)
+		}
+	}
+	purchasePriceChance := 1 / float64(len(purchasePrices))
+
+	for _, purchasePrice := range purchasePrices {
+		ticker := predictor.Ticker
+		if ticker.PurchasePrice != purchasePrice {
+			purchaseTicker := *ticker
+			purchaseTicker.PurchasePrice = purchasePrice
+			ticker = &purchaseTicker
+		}
+
+		// Get the base phase progression of this pattern
+		patternPhases := predictor.Definition.PhaseProgression(ticker)
+		predictor.shareCache(patternPhases)
+		brancher := &phaseBrancher{
+			onComplete: func(patternPhases []PatternPhase) {
+				predictor.addWeekFromFinalizedPhases(
+					ticker, purchasePriceChance, patternPhases,
+				)
+			},
+		}
+		brancher.branchPhases(patternPhases)
+	}
+
+	predictor.freezePhases()
 
 	// Store the total width in the analysis object for now
 	predictor.result.chance = predictor.binWidth
+	predictor.result.excludedWeeks = predictor.excludedWeeks
 	return predictor.result, predictor.binWidth
 }
@@ -0,0 +1,72 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPurchasePriceKnown(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := (&Predictor{Ticker: NewTicker(97, UNKNOWN, 0)}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	assert.Equal(97, prediction.PurchasePrices.MinPrice())
+	assert.Equal(97, prediction.PurchasePrices.MaxPrice())
+	assert.Equal(1.0, prediction.PurchasePrices.Chance(97))
+
+	for _, pattern := range []PricePattern{FLUCTUATING, BIGSPIKE, SMALLSPIKE} {
+		for _, week := range prediction.Patterns[pattern].PotentialWeeks {
+			assert.Equal(97, week.PurchasePrice)
+		}
+	}
+}
+
+func TestPurchasePriceUnknownNoPrices(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := (&Predictor{Ticker: NewTicker(0, UNKNOWN, 0)}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	// With nothing to go on, every price Daisy Mae offers is as likely as the next.
+	purchasePrices := prediction.PurchasePrices
+	assert.Equal(90, purchasePrices.MinPrice())
+	assert.Equal(110, purchasePrices.MaxPrice())
+	for price := 90; price <= 110; price++ {
+		assert.InDelta(1.0/21, purchasePrices.Chance(price), 0.00001, price)
+	}
+
+	// The pattern chances should be unchanged by the purchase price prior.
+	assert.InDelta(0.35, prediction.Patterns[FLUCTUATING].Chance(), 0.0001)
+	assert.InDelta(0.2625, prediction.Patterns[BIGSPIKE].Chance(), 0.0001)
+	assert.InDelta(0.1375, prediction.Patterns[DECREASING].Chance(), 0.0001)
+	assert.InDelta(0.25, prediction.Patterns[SMALLSPIKE].Chance(), 0.0001)
+}
+
+func TestPurchasePriceUnknownPosterior(t *testing.T) {
+	assert := assert.New(t)
+
+	// A price of 40 on monday morning is only possible at the very bottom of a small
+	// spike's opening decline, which can't reach 40 with a high purchase price.
+	ticker := NewTicker(0, UNKNOWN, 0)
+	ticker.Prices[0] = 40
+
+	prediction, err := (&Predictor{Ticker: ticker}).Predict()
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	purchasePrices := prediction.PurchasePrices
+	assert.Equal(90, purchasePrices.MinPrice())
+	assert.Less(purchasePrices.MaxPrice(), 100)
+	assert.Equal(0.0, purchasePrices.Chance(110))
+	assert.Less(purchasePrices.Expected(), 95.0)
+	assert.Greater(purchasePrices.Chance(90), purchasePrices.Chance(99))
+}
@@ -0,0 +1,119 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Returns a random int from low to high, inclusive.
+func randomInt(random *rand.Rand, low int, high int) int {
+	return low + random.Intn(high-low+1)
+}
+
+// Makes a ticker that may or may not be valid. About half of the tickers have
+// something wrong with them, picked from a little past the edges of what is valid,
+// and a few are missing altogether.
+func randomTicker(random *rand.Rand) *PriceTicker {
+	ticker := &PriceTicker{
+		PurchasePrice:   randomInt(random, 90, 110),
+		PreviousPattern: PricePattern(randomInt(random, 0, 4)),
+		CurrentPeriod:   PricePeriod(randomInt(random, 0, 11)),
+	}
+	// Unknown purchase prices make for slow predictions, so we only want a few.
+	if random.Intn(10) == 0 {
+		ticker.PurchasePrice = 0
+	}
+
+	for period := PricePeriod(0); period <= ticker.CurrentPeriod; period++ {
+		switch random.Intn(4) {
+		case 0, 1:
+			ticker.Prices[period] = randomInt(random, 40, 200)
+		case 2:
+			low := randomInt(random, 40, 200)
+			ticker.Intervals[period] = PriceInterval{
+				Min: low, Max: low + randomInt(random, 0, 30),
+			}
+		}
+	}
+
+	period := random.Intn(len(ticker.Prices))
+	switch random.Intn(12) {
+	case 0:
+		ticker.PurchasePrice = randomInt(random, -20, 200)
+	case 1:
+		ticker.PreviousPattern = PricePattern(randomInt(random, -10, 10))
+	case 2:
+		ticker.CurrentPeriod = PricePeriod(randomInt(random, -10, 20))
+	case 3:
+		ticker.Prices[period] = randomInt(random, -100, 1000)
+	case 4:
+		ticker.Intervals[period] = PriceInterval{
+			Min: randomInt(random, -50, 700), Max: randomInt(random, -50, 700),
+		}
+	case 5:
+		ticker.Prices[period] = randomInt(random, 1, 660)
+	case 6:
+		return nil
+	}
+
+	return ticker
+}
+
+func randomErrorRate(random *rand.Rand) float64 {
+	rates := []float64{0, 0, 0, 0, 0, 0.05, 0.05, -0.1, 1, math.NaN()}
+	return rates[random.Intn(len(rates))]
+}
+
+// Feeds random tickers to the predictor. None of them should panic, and any that
+// can't be predicted should say why.
+func TestPredictRandomTickers(t *testing.T) {
+	assert := assert.New(t)
+
+	count := 300
+	if testing.Short() {
+		count = 50
+	}
+
+	random := rand.New(rand.NewSource(46))
+	expectedErrs := []error{
+		errs.ErrInvalidTicker,
+		errs.ErrInvalidErrorRate,
+		errs.ErrImpossibleTickerPrices,
+	}
+
+	for i := 0; i < count; i++ {
+		predictor := &Predictor{
+			Ticker:         randomTicker(random),
+			PredictOptions: PredictOptions{ErrorRate: randomErrorRate(random)},
+		}
+
+		var prediction *Prediction
+		var err error
+		if !assert.NotPanics(
+			func() { prediction, err = predictor.Predict() },
+			"ticker %v: %+v", i, predictor.Ticker,
+		) {
+			continue
+		}
+
+		if err == nil {
+			assert.NotEmpty(prediction.Patterns, "ticker %v", i)
+			continue
+		}
+
+		known := false
+		for _, expected := range expectedErrs {
+			if xerrors.Is(err, expected) {
+				known = true
+			}
+		}
+		assert.True(known, "ticker %v: unexpected error: %v", i, err)
+	}
+}
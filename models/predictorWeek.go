@@ -1,11 +1,41 @@
 package models
 
+import (
+	"github.com/peake100/turnup-go/values"
+)
+
+// The highest price the game can generate: a 6x multiplier on a 110 bell purchase
+// price. A mistaken price is treated as equally likely to be any price up to this.
+const maxPossiblePrice = 660
+
+// The chance a mistaken price lands from ``low`` to ``high`` (inclusive) when every
+// price up to maxPossiblePrice is equally likely. Prices over maxPossiblePrice are
+// counted as if they were in range, since a typo can land anywhere.
+func mistakeRangeChance(low int, high int) float64 {
+	if low < 1 {
+		low = 1
+	}
+	if high == 0 && low < maxPossiblePrice {
+		high = maxPossiblePrice
+	} else if high == 0 {
+		high = low
+	}
+
+	chance := float64(high-low+1) / maxPossiblePrice
+	if chance > 1 {
+		chance = 1
+	}
+	return chance
+}
+
 // Handles doing predictions for a potential phase permutation of a week once the phase
 // pattern is finalized
 type weekPredictor struct {
 	Ticker        *PriceTicker
-	Pattern       PricePattern
+	Definition    *PatternDefinition
+	BaseChance    float64
 	PatternPhases []PatternPhase
+	Options       *PredictOptions
 
 	// Value cache
 	// The probability weight of the price pattern given last week's pattern
@@ -17,6 +47,14 @@ type weekPredictor struct {
 	binWidth float64
 	// Set to true if there are any known prices in the ticker
 	pricesKnown bool
+	// Set to true if the week was removed by a constraint
+	excluded bool
+	// The period whose price the week could not make, if it was thrown out because
+	// of a ticker price.
+	ruledOutBy *PotentialPricePeriod
+	// The chance that every known price which doesn't fit this week is a mistake,
+	// when prices might be mistakes.
+	noisyWidth float64
 
 	result *PotentialWeek
 }
@@ -29,10 +67,12 @@ func (predictor *weekPredictor) increaseBinWidth(amount float64) {
 // score the bin "width"
 func (predictor *weekPredictor) addPeriodBinWidth(
 	pricePeriod PricePeriod,
-	knownPrice int,
+	low int,
+	high int,
+	known bool,
 ) {
-	// If this price is unknown (0) then we can't make probability estimates with it.
-	if knownPrice == 0 {
+	// If this price is unknown then we can't make probability estimates with it.
+	if !known {
 		return
 	}
 
@@ -52,12 +92,33 @@ func (predictor *weekPredictor) addPeriodBinWidth(
 	// the price math is implemented. We divide by the period range to get the
 	// likelihood that this price would occur in this range relative to other
 	// ranges.
-	priceChance := prices.PriceChance(knownPrice)
+	//
+	// If we only know the price fell within an interval, we add up the chance of
+	// every price in the interval. For an exact price, low and high are the same.
+	priceChance := prices.rangeChance(low, high)
 	periodWidth := 0.0
 	if priceChance != 0.0 {
 		periodWidth = priceChance / float64(periodRange)
 	}
 
+	// If prices might be mistakes, the price either came from this period's bracket
+	// or was entered wrong, in which case it could have been anything.
+	//
+	// Without an error rate, a week that can't make one of the prices is thrown out.
+	// With one, that price can only be a mistake, so the week keeps the chance of a
+	// mistake instead. Every price that does not fit lowers the chance of the week,
+	// which lets us tell which price is the mistake, and the widths we add up become
+	// the exact ones as the error rate goes to 0.
+	if errorRate := predictor.Options.ErrorRate; errorRate > 0 {
+		mistakeWidth := errorRate * mistakeRangeChance(low, high)
+		periodWidth = (1-errorRate)*periodWidth + mistakeWidth
+		predictor.result.MistakeChances[pricePeriod] = mistakeWidth / periodWidth
+		if !prices.IsValidRange(low, high) {
+			predictor.noisyWidth *= errorRate
+		}
+	}
+	predictor.result.PeriodLikelihoods[pricePeriod] = periodWidth
+
 	// Weight it by the likelihood of this pattern occurring in the first
 	// place
 	periodWidth *= predictor.patternWeight
@@ -84,10 +145,13 @@ func (predictor *weekPredictor) buildWeek() {
 			potentialPeriod := thisPhase.PotentialPeriod(pricePeriod, phasePeriod)
 
 			// If this is not a valid price, we set the result to nil and stop making
-			// predictions
-			knownPrice := ticker.Prices[pricePeriod]
-			if !potentialPeriod.IsValidPrice(knownPrice) {
+			// predictions. If prices might be mistakes, the price will lower the chance
+			// of this week instead.
+			low, high, known := ticker.observation(pricePeriod)
+			if !potentialPeriod.IsValidRange(low, high) &&
+				predictor.Options.ErrorRate == 0 {
 				predictor.result = nil
+				predictor.ruledOutBy = potentialPeriod
 				return
 			}
 
@@ -102,7 +166,7 @@ func (predictor *weekPredictor) buildWeek() {
 			)
 
 			// Now get the probability width that this week will happen
-			predictor.addPeriodBinWidth(pricePeriod, knownPrice)
+			predictor.addPeriodBinWidth(pricePeriod, low, high, known)
 
 			// Increment the overall price period
 			pricePeriod++
@@ -116,30 +180,68 @@ func (predictor *weekPredictor) finalizeWidth() {
 	// happen, but are vanishingly unlikely will have an effective width of 0.s
 	if !predictor.pricesKnown {
 		predictor.binWidth = predictor.patternWeight
+	} else if predictor.Options.ErrorRate > 0 {
+		predictor.binWidth *= predictor.noisyWidth
 	}
 
 	// Now weight each week by the number of possible weeks for this pattern. As we
 	// knock out possible phase combinations for a pattern, the likelihood of this
 	// pattern goes down.
 	predictor.binWidth /= float64(predictor.patternPermutationCount)
+	predictor.result.prior = predictor.patternWeight /
+		float64(predictor.patternPermutationCount)
 
 	// Use this bin width as our chance for now.
 	predictor.result.chance = predictor.binWidth
 }
 
+// Re-weights the finished week by each constraint. The week is excluded as soon as a
+// constraint gives it a weight of 0.
+func (predictor *weekPredictor) applyConstraints() {
+	predictor.result.constraintWeight = 1
+	for _, constraint := range predictor.Options.Constraints {
+		weight := constraint.Weight(predictor.result)
+		if weight <= 0 {
+			predictor.excluded = true
+			predictor.result = nil
+			predictor.binWidth = 0
+			return
+		}
+		predictor.binWidth *= weight
+		predictor.result.constraintWeight *= weight
+	}
+	predictor.result.chance = predictor.binWidth
+}
+
+// Everything a potential week points to, so a week can be made with a single
+// allocation. Most weeks are thrown out after a period or two, so this saves a lot of
+// garbage.
+type weekAlloc struct {
+	week     PotentialWeek
+	analysis Analysis
+	spikes   SpikeRangeAll
+	big      SpikeRange
+	small    SpikeRange
+	any      SpikeRange
+	prices   [values.PricePeriodCount]*PotentialPricePeriod
+}
+
 func (predictor *weekPredictor) setup() {
-	predictor.result = &PotentialWeek{
-		Analysis: NewAnalysis(predictor.Ticker),
-		Spikes: &SpikeRangeAll{
-			big:   new(SpikeRange),
-			small: new(SpikeRange),
-			any:   new(SpikeRange),
-		},
+	predictor.noisyWidth = 1
+
+	alloc := new(weekAlloc)
+	alloc.analysis.setup(predictor.Ticker)
+	alloc.spikes = SpikeRangeAll{big: &alloc.big, small: &alloc.small, any: &alloc.any}
+	alloc.week = PotentialWeek{
+		Analysis:      &alloc.analysis,
+		Pattern:       predictor.Definition.Pattern,
+		PurchasePrice: predictor.Ticker.PurchasePrice,
+		Spikes:        &alloc.spikes,
+		Prices:        alloc.prices[:0],
 	}
-	predictor.patternWeight = predictor.Pattern.BaseChance(
-		predictor.Ticker.PreviousPattern,
-	)
-	predictor.patternPermutationCount = predictor.Pattern.PermutationCount()
+	predictor.result = &alloc.week
+	predictor.patternWeight = predictor.BaseChance
+	predictor.patternPermutationCount = predictor.Definition.PermutationCount()
 }
 
 func (predictor *weekPredictor) Predict() (
@@ -149,6 +251,8 @@ func (predictor *weekPredictor) Predict() (
 	predictor.buildWeek()
 	if predictor.result != nil {
 		predictor.finalizeWidth()
+		predictor.result.Bands = newWeekBands(predictor.result, predictor.Ticker)
+		predictor.applyConstraints()
 	}
 	return predictor.result, predictor.binWidth
 }
@@ -0,0 +1,233 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/models/timeofday"
+	"github.com/peake100/turnup-go/values"
+	"time"
+)
+
+// PriceBands holds the probability distribution of bell prices for each price period of
+// the week, as well as for the highest price of the week. Unlike the min, guaranteed
+// and max prices, which only describe the extreme ends of what might happen, these
+// distributions can be used to draw percentile fan charts.
+type PriceBands struct {
+	periods   [values.PricePeriodCount]*PriceDistribution
+	weeklyMax *PriceDistribution
+	futureMax *PriceDistribution
+
+	// The current period of the ticker these bands were made for.
+	currentPeriod PricePeriod
+}
+
+// The price distribution for a given price period. Returns an empty distribution if
+// the period is out of range.
+func (bands *PriceBands) Period(period PricePeriod) *PriceDistribution {
+	if period < 0 || int(period) >= len(bands.periods) {
+		return new(PriceDistribution)
+	}
+	return bands.periods[period]
+}
+
+// Return the price distribution for a given Weekday + time of day
+func (bands *PriceBands) ForDay(
+	weekday time.Weekday, tod timeofday.ToD,
+) (dist *PriceDistribution, err error) {
+	pricePeriod, err := PricePeriodFromDay(weekday, tod)
+	if err != nil {
+		return nil, err
+	}
+	return bands.periods[pricePeriod], nil
+}
+
+// Return the price distribution for a given time. The ticker does not contain any
+// information about dates, so it is assumed that the time passed in to priceTime is
+// for the week that the bands describe.
+func (bands *PriceBands) ForTime(
+	priceTime time.Time,
+) (dist *PriceDistribution, err error) {
+	pricePeriod, err := PricePeriodFromTime(priceTime)
+	if err != nil {
+		return nil, err
+	}
+	return bands.periods[pricePeriod], nil
+}
+
+// The distribution of the highest price over all 12 price periods of the week. The
+// prices of a week are tied together by its phases, which is taken into account. If
+// the prediction was made with an ErrorRate, known prices that may be mistakes make
+// this an approximation.
+func (bands *PriceBands) WeeklyMax() *PriceDistribution {
+	return bands.weeklyMax
+}
+
+// The distribution of the highest price from the ticker's current price period to
+// the end of the week. Worked out the same way as WeeklyMax.
+func (bands *PriceBands) FutureMax() *PriceDistribution {
+	return bands.futureMax
+}
+
+// Builds the price bands for a single potential week. Known prices are certain, while
+// unknown prices are distributed over the bracket of their potential price period.
+// Prices known to fall within an interval are limited to that interval.
+func newWeekBands(week *PotentialWeek, ticker *PriceTicker) *PriceBands {
+	bands := &PriceBands{currentPeriod: ticker.CurrentPeriod}
+
+	for i, potentialPeriod := range week.Prices {
+		var dist *PriceDistribution
+		low, high, known := ticker.observation(PricePeriod(i))
+		switch {
+		case known && low == high:
+			dist = newPointDistribution(low)
+		case known:
+			dist = newPeriodDistribution(&potentialPeriod.pricesVal)
+			dist.truncate(low, high)
+		default:
+			dist = newPeriodDistribution(&potentialPeriod.pricesVal)
+		}
+
+		// If the known price might be a mistake, the real price might have been
+		// anything in the period's bracket.
+		if mistakeChance := week.MistakeChances[i]; known && mistakeChance > 0 {
+			mixer := new(distributionMixer)
+			mixer.add(dist, 1-mistakeChance)
+			mixer.add(newPeriodDistribution(&potentialPeriod.pricesVal), mistakeChance)
+			dist = mixer.distribution()
+		}
+		bands.periods[i] = dist
+	}
+
+	lastPeriod := PricePeriod(values.PricePeriodCount - 1)
+	bands.weeklyMax = newWeekMaxDistribution(week, &bands.periods, 0, lastPeriod)
+	bands.futureMax = newWeekMaxDistribution(
+		week, &bands.periods, bands.futureStart(), lastPeriod,
+	)
+	return bands
+}
+
+// The first period of the future: the ticker's current period.
+func (bands *PriceBands) futureStart() PricePeriod {
+	if bands.currentPeriod < 0 {
+		return 0
+	}
+	return bands.currentPeriod
+}
+
+// Works out the distribution of the highest price of a week from ``start`` to ``end``
+// (inclusive). The prices of a week are not independent, so we first leave out every
+// period that can never be higher than another period in range:
+//
+//   - A period of a phase that compounds downwards can't be higher than the period
+//     before it.
+//   - The peak of a spike is the highest of the spike's periods.
+//   - A period can't be higher than another whose lowest price is its highest price.
+//
+// For the in-game patterns, the game draws the prices of the periods that are left
+// independently of each other, so they can be combined as such.
+func newWeekMaxDistribution(
+	week *PotentialWeek,
+	dists *[values.PricePeriodCount]*PriceDistribution,
+	start PricePeriod,
+	end PricePeriod,
+) *PriceDistribution {
+	if end >= values.PricePeriodCount {
+		end = values.PricePeriodCount - 1
+	}
+
+	candidates := make([]*PriceDistribution, 0, values.PricePeriodCount)
+	for period := start; period <= end; period++ {
+		if weekPeriodMayBeMax(week, dists, start, end, period) {
+			candidates = append(candidates, dists[period])
+		}
+	}
+
+	// Prices that may be mistakes can rule each other out. If that leaves us with
+	// nothing, we fall back to treating every period as independent.
+	if len(candidates) == 0 && start <= end {
+		candidates = append(candidates, dists[start:end+1]...)
+	}
+	return newMaxDistribution(candidates)
+}
+
+// Whether the price of ``period`` could be the highest price of the week from
+// ``start`` to ``end``.
+func weekPeriodMayBeMax(
+	week *PotentialWeek,
+	dists *[values.PricePeriodCount]*PriceDistribution,
+	start PricePeriod,
+	end PricePeriod,
+	period PricePeriod,
+) bool {
+	potential := week.Prices[period]
+	if potential.falls && period > start &&
+		week.Prices[period-1].PatternPhase == potential.PatternPhase {
+		return false
+	}
+
+	isSpike := potential.Spikes.Any().Has() && !potential.Spikes.IsPeak()
+	dist := dists[period]
+	for other := start; other <= end; other++ {
+		if other == period {
+			continue
+		}
+
+		otherPotential := week.Prices[other]
+		if isSpike && otherPotential.Spikes.IsPeak() &&
+			otherPotential.PatternPhase == potential.PatternPhase {
+			return false
+		}
+
+		// When two periods can only be the same price, we keep the first.
+		otherDist := dists[other]
+		if otherDist.MinPrice() >= dist.MaxPrice() &&
+			(otherDist.MinPrice() > dist.MinPrice() || other < period) {
+			return false
+		}
+	}
+	return true
+}
+
+// Mixes the bands of many potential weeks, weighting each by the chance of the week.
+type bandsMixer struct {
+	periods   [values.PricePeriodCount]distributionMixer
+	weeklyMax distributionMixer
+	futureMax distributionMixer
+}
+
+func (mixer *bandsMixer) add(bands *PriceBands, weight float64) {
+	for i, dist := range bands.periods {
+		mixer.periods[i].add(dist, weight)
+	}
+	mixer.weeklyMax.add(bands.weeklyMax, weight)
+	mixer.futureMax.add(bands.futureMax, weight)
+}
+
+func (mixer *bandsMixer) bands() *PriceBands {
+	bands := new(PriceBands)
+	for i := range mixer.periods {
+		bands.periods[i] = mixer.periods[i].distribution()
+	}
+	bands.weeklyMax = mixer.weeklyMax.distribution()
+	bands.futureMax = mixer.futureMax.distribution()
+	return bands
+}
+
+// Rolls up the bands of potential weeks into a single set of bands, weighted by the
+// chance of each week. If every week has a chance of 0 (which can happen when chances
+// are rounded), each week is weighted equally instead.
+func mixWeekBands(weeks []*PotentialWeek) *PriceBands {
+	totalChance := 0.0
+	for _, week := range weeks {
+		totalChance += week.Chance()
+	}
+
+	mixer := new(bandsMixer)
+	for _, week := range weeks {
+		weight := week.Chance()
+		if totalChance <= 0 {
+			weight = 1
+		}
+		mixer.add(week.Bands, weight)
+	}
+
+	return mixer.bands()
+}
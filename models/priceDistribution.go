@@ -0,0 +1,308 @@
+package models
+
+// Tolerance for accumulated floating point error when walking cumulative chances.
+const distributionEpsilon = 1e-9
+
+// PriceDistribution is a discrete probability distribution over bell prices. Chances
+// are stored densely, starting at the lowest possible price, so that cumulative
+// queries like percentiles can be answered by walking the slice in order.
+type PriceDistribution struct {
+	// The lowest price with a chance of occurring. chances[0] is the chance of this
+	// price, chances[1] the chance of minPrice + 1, etc.
+	minPrice int
+	chances  []float64
+}
+
+// The lowest price that has a chance of occurring. Returns 0 if the distribution is
+// empty.
+func (dist *PriceDistribution) MinPrice() int {
+	if len(dist.chances) == 0 {
+		return 0
+	}
+	return dist.minPrice
+}
+
+// The highest price that has a chance of occurring. Returns 0 if the distribution is
+// empty.
+func (dist *PriceDistribution) MaxPrice() int {
+	if len(dist.chances) == 0 {
+		return 0
+	}
+	return dist.minPrice + len(dist.chances) - 1
+}
+
+// Whether the distribution has no possible prices. Distributions for patterns that
+// have been eliminated by the ticker will be empty.
+func (dist *PriceDistribution) IsEmpty() bool {
+	return len(dist.chances) == 0
+}
+
+// The chance from 0.0-1.0 that exactly ``price`` will occur.
+func (dist *PriceDistribution) Chance(price int) float64 {
+	index := price - dist.minPrice
+	if index < 0 || index >= len(dist.chances) {
+		return 0
+	}
+	return dist.chances[index]
+}
+
+// The chance from 0.0-1.0 that a price of ``price`` or higher will occur.
+func (dist *PriceDistribution) ChanceAtLeast(price int) float64 {
+	var chance float64
+	for i := len(dist.chances) - 1; i >= 0 && dist.minPrice+i >= price; i-- {
+		chance += dist.chances[i]
+	}
+	// Guard against floating point drift pushing us over 1.
+	if chance > 1 {
+		chance = 1
+	}
+	return chance
+}
+
+// The chance from 0.0-1.0 that a price of ``price`` or lower will occur.
+func (dist *PriceDistribution) ChanceAtMost(price int) float64 {
+	return 1 - dist.ChanceAtLeast(price+1)
+}
+
+// Returns the lowest price for which the chance of a price at or below it is at least
+// ``fraction``. A fraction of 0.5 returns the median price, 0.9 the 90th percentile,
+// etc. Returns 0 if the distribution is empty.
+func (dist *PriceDistribution) Quantile(fraction float64) int {
+	var cumulative float64
+	for i, chance := range dist.chances {
+		cumulative += chance
+		if cumulative >= fraction-distributionEpsilon {
+			return dist.minPrice + i
+		}
+	}
+	return dist.MaxPrice()
+}
+
+// The probability-weighted average price. Returns 0 if the distribution is empty.
+func (dist *PriceDistribution) Expected() float64 {
+	var expected float64
+	for i, chance := range dist.chances {
+		expected += float64(dist.minPrice+i) * chance
+	}
+	return expected
+}
+
+// Summarizes the distribution with the percentiles commonly drawn on a fan chart.
+func (dist *PriceDistribution) Band() PriceBand {
+	return PriceBand{
+		P10:      dist.Quantile(0.10),
+		P25:      dist.Quantile(0.25),
+		P50:      dist.Quantile(0.50),
+		P75:      dist.Quantile(0.75),
+		P90:      dist.Quantile(0.90),
+		Expected: dist.Expected(),
+	}
+}
+
+// Removes zero-chance prices from either end of the distribution so MinPrice() and
+// MaxPrice() report the actual possible prices.
+func (dist *PriceDistribution) trim() {
+	start := 0
+	for start < len(dist.chances) && dist.chances[start] <= 0 {
+		start++
+	}
+	end := len(dist.chances)
+	for end > start && dist.chances[end-1] <= 0 {
+		end--
+	}
+	dist.minPrice += start
+	dist.chances = dist.chances[start:end]
+}
+
+// Scales all chances so they sum to 1. If the chances sum to 0, every price in the
+// distribution is treated as equally likely.
+func (dist *PriceDistribution) normalize() {
+	var total float64
+	for _, chance := range dist.chances {
+		total += chance
+	}
+
+	if total <= 0 {
+		for i := range dist.chances {
+			dist.chances[i] = 1 / float64(len(dist.chances))
+		}
+		return
+	}
+
+	for i := range dist.chances {
+		dist.chances[i] /= total
+	}
+}
+
+// Limits the distribution to prices from ``low`` to ``high`` (inclusive) and
+// re-normalizes it. A bound of 0 is open-ended.
+func (dist *PriceDistribution) truncate(low int, high int) {
+	for i := range dist.chances {
+		price := dist.minPrice + i
+		if (low != 0 && price < low) || (high != 0 && price > high) {
+			dist.chances[i] = 0
+		}
+	}
+	dist.trim()
+	if len(dist.chances) > 0 {
+		dist.normalize()
+	}
+}
+
+// PriceBand is the percentile summary of a PriceDistribution.
+type PriceBand struct {
+	P10 int
+	P25 int
+	P50 int
+	P75 int
+	P90 int
+
+	// The probability-weighted average price.
+	Expected float64
+}
+
+// Creates a distribution where ``price`` is certain.
+func newPointDistribution(price int) *PriceDistribution {
+	return &PriceDistribution{
+		minPrice: price,
+		chances:  []float64{1},
+	}
+}
+
+// Creates the distribution for the bell bracket of a potential price period. The
+// lowest and highest prices get their own bin-width chances, and every price in
+// between shares the middle chance equally.
+func newPeriodDistribution(prices *pricesVal) *PriceDistribution {
+	low := prices.GuaranteedPrice()
+	high := prices.MaxPrice()
+	count := high - low + 1
+	if count <= 1 {
+		return newPointDistribution(high)
+	}
+
+	dist := &PriceDistribution{
+		minPrice: low,
+		chances:  make([]float64, count),
+	}
+
+	midEach := 0.0
+	if count > 2 {
+		midEach = prices.midChance / float64(count-2)
+	}
+	for i := 1; i < count-1; i++ {
+		dist.chances[i] = midEach
+	}
+	dist.chances[0] = prices.minChance
+	dist.chances[count-1] = prices.maxChance
+
+	dist.normalize()
+	dist.trim()
+	return dist
+}
+
+// Creates the distribution of the highest price among several independent
+// distributions. The chance the max is at or below a price is the product of the
+// chance each distribution is at or below that price.
+func newMaxDistribution(dists []*PriceDistribution) *PriceDistribution {
+	low, high := 0, 0
+	found := false
+	for _, dist := range dists {
+		if dist.IsEmpty() {
+			continue
+		}
+		if !found || dist.MinPrice() > low {
+			low = dist.MinPrice()
+		}
+		if !found || dist.MaxPrice() > high {
+			high = dist.MaxPrice()
+		}
+		found = true
+	}
+
+	result := new(PriceDistribution)
+	if !found {
+		return result
+	}
+
+	result.minPrice = low
+	result.chances = make([]float64, high-low+1)
+
+	previousCumulative := 0.0
+	for price := low; price <= high; price++ {
+		cumulative := 1.0
+		for _, dist := range dists {
+			if dist.IsEmpty() {
+				continue
+			}
+			cumulative *= dist.ChanceAtMost(price)
+		}
+		result.chances[price-low] = cumulative - previousCumulative
+		previousCumulative = cumulative
+	}
+
+	result.normalize()
+	result.trim()
+	return result
+}
+
+// Accumulates a weighted mixture of distributions. Used to roll up week distributions
+// into pattern and prediction distributions.
+type distributionMixer struct {
+	// Weighted chances, starting at minPrice
+	minPrice    int
+	chances     []float64
+	totalWeight float64
+}
+
+// Grows the chance slice so it covers low through high.
+func (mixer *distributionMixer) cover(low int, high int) {
+	if len(mixer.chances) == 0 {
+		mixer.minPrice = low
+		mixer.chances = make([]float64, high-low+1)
+		return
+	}
+
+	currentHigh := mixer.minPrice + len(mixer.chances) - 1
+	if low >= mixer.minPrice && high <= currentHigh {
+		return
+	}
+
+	if low > mixer.minPrice {
+		low = mixer.minPrice
+	}
+	if high < currentHigh {
+		high = currentHigh
+	}
+
+	grown := make([]float64, high-low+1)
+	copy(grown[mixer.minPrice-low:], mixer.chances)
+	mixer.minPrice = low
+	mixer.chances = grown
+}
+
+func (mixer *distributionMixer) add(dist *PriceDistribution, weight float64) {
+	if dist.IsEmpty() {
+		return
+	}
+
+	mixer.cover(dist.MinPrice(), dist.MaxPrice())
+	offset := dist.minPrice - mixer.minPrice
+	for i, chance := range dist.chances {
+		mixer.chances[offset+i] += chance * weight
+	}
+	mixer.totalWeight += weight
+}
+
+func (mixer *distributionMixer) distribution() *PriceDistribution {
+	result := &PriceDistribution{
+		minPrice: mixer.minPrice,
+		chances:  mixer.chances,
+	}
+	if len(result.chances) == 0 {
+		return result
+	}
+
+	result.normalize()
+	result.trim()
+	return result
+}
@@ -0,0 +1,100 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPriceDistributionPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	// 10 possible prices, the lowest and highest of which are half as likely as the
+	// rest.
+	dist := newPeriodDistribution(&pricesVal{
+		guaranteedPrice: 100,
+		maxPrice:        109,
+		minChance:       0.5 / 9,
+		midChance:       8.0 / 9,
+		maxChance:       0.5 / 9,
+	})
+
+	assert.Equal(100, dist.MinPrice(), "min price")
+	assert.Equal(109, dist.MaxPrice(), "max price")
+	assert.InDelta(0.5/9, dist.Chance(100), 0.00001, "min chance")
+	assert.InDelta(1.0/9, dist.Chance(105), 0.00001, "mid chance")
+	assert.Equal(0.0, dist.Chance(110), "out of range chance")
+	assert.InDelta(104.5, dist.Expected(), 0.00001, "expected")
+	assert.InDelta(1.0, dist.ChanceAtLeast(100), 0.00001, "chance at least min")
+	assert.InDelta(0.5/9, dist.ChanceAtLeast(109), 0.00001, "chance at least max")
+
+	band := dist.Band()
+	assert.Equal(101, band.P10, "p10")
+	assert.Equal(104, band.P50, "p50")
+	assert.Equal(108, band.P90, "p90")
+}
+
+func TestPriceDistributionPeriodZeroWidthEdges(t *testing.T) {
+	assert := assert.New(t)
+
+	// If the extreme prices of a bracket can only be hit by an exact float, they
+	// should be trimmed from the distribution.
+	dist := newPeriodDistribution(&pricesVal{
+		guaranteedPrice: 100,
+		maxPrice:        103,
+		minChance:       0,
+		midChance:       1,
+		maxChance:       0,
+	})
+
+	assert.Equal(101, dist.MinPrice(), "min price")
+	assert.Equal(102, dist.MaxPrice(), "max price")
+	assert.Equal(0.5, dist.Chance(101), "mid chance")
+}
+
+func TestPriceDistributionMax(t *testing.T) {
+	assert := assert.New(t)
+
+	dist := newMaxDistribution([]*PriceDistribution{
+		{minPrice: 1, chances: []float64{0.5, 0.5}},
+		{minPrice: 1, chances: []float64{0.5, 0.5}},
+		new(PriceDistribution),
+	})
+
+	assert.Equal(1, dist.MinPrice(), "min price")
+	assert.Equal(2, dist.MaxPrice(), "max price")
+	assert.InDelta(0.25, dist.Chance(1), 0.00001, "both low")
+	assert.InDelta(0.75, dist.Chance(2), 0.00001, "either high")
+}
+
+func TestPriceDistributionMixer(t *testing.T) {
+	assert := assert.New(t)
+
+	mixer := new(distributionMixer)
+	mixer.add(newPointDistribution(100), 0.75)
+	mixer.add(newPointDistribution(200), 0.25)
+	mixer.add(new(PriceDistribution), 1)
+
+	dist := mixer.distribution()
+	assert.Equal(100, dist.MinPrice(), "min price")
+	assert.Equal(200, dist.MaxPrice(), "max price")
+	assert.Equal(0.75, dist.Chance(100), "low chance")
+	assert.Equal(0.0, dist.Chance(150), "gap chance")
+	assert.Equal(125.0, dist.Expected(), "expected")
+	assert.Equal(100, dist.Quantile(0.75), "quantile 75")
+	assert.Equal(200, dist.Quantile(0.76), "quantile 76")
+}
+
+func TestPriceDistributionEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	dist := new(distributionMixer).distribution()
+	assert.True(dist.IsEmpty(), "empty")
+	assert.Equal(0, dist.MinPrice(), "min price")
+	assert.Equal(0, dist.MaxPrice(), "max price")
+	assert.Equal(0, dist.Quantile(0.5), "quantile")
+	assert.Equal(0.0, dist.Expected(), "expected")
+	assert.Equal(0.0, dist.ChanceAtLeast(0), "chance at least")
+}
@@ -77,6 +77,7 @@ type SpikeHasAll struct {
 	big   *Spike
 	small *Spike
 	any   *Spike
+	peak  bool
 }
 
 func (spikes *SpikeHasAll) Big() HasSpike {
@@ -91,6 +92,44 @@ func (spikes *SpikeHasAll) Any() HasSpike {
 	return spikes.any
 }
 
+// Whether this is the period a spike reaches its highest price.
+func (spikes *SpikeHasAll) IsPeak() bool {
+	return spikes.peak
+}
+
+// Every potential period shares one of these spike flags, so we don't need to allocate
+// them for each period. They must never be changed.
+var (
+	spikeNone = &Spike{has: false}
+	spikeSome = &Spike{has: true}
+
+	spikeFlagsNone    = &SpikeHasAll{big: spikeNone, small: spikeNone, any: spikeNone}
+	spikeFlagsBig     = &SpikeHasAll{big: spikeSome, small: spikeNone, any: spikeSome}
+	spikeFlagsSmall   = &SpikeHasAll{big: spikeNone, small: spikeSome, any: spikeSome}
+	spikeFlagsBigPeak = &SpikeHasAll{
+		big: spikeSome, small: spikeNone, any: spikeSome, peak: true,
+	}
+	spikeFlagsSmallPeak = &SpikeHasAll{
+		big: spikeNone, small: spikeSome, any: spikeSome, peak: true,
+	}
+)
+
+// Returns the shared spike flags for a period.
+func spikeFlags(isSpike bool, isBig bool, isPeak bool) *SpikeHasAll {
+	switch {
+	case !isSpike:
+		return spikeFlagsNone
+	case isBig && isPeak:
+		return spikeFlagsBigPeak
+	case isBig:
+		return spikeFlagsBig
+	case isPeak:
+		return spikeFlagsSmallPeak
+	default:
+		return spikeFlagsSmall
+	}
+}
+
 type SpikeRangeAll struct {
 	big   *SpikeRange
 	small *SpikeRange
@@ -2,6 +2,8 @@ package models
 
 import (
 	"github.com/peake100/turnup-go/values"
+	"strconv"
+	"strings"
 )
 
 type PriceTicker struct {
@@ -23,6 +25,11 @@ type PriceTicker struct {
 	// Because PricePeriod is an extension of int, we can access the array with
 	// PricePeriod objects.
 	Prices NookPriceArray
+
+	// Ranges a price is known to fall in, for periods where we do not know the exact
+	// price. Ignored for any period with a price in Prices. See SetRange(),
+	// SetAtLeast(), SetAtMost() and SetAround().
+	Intervals PriceIntervalArray
 }
 
 func NewTicker(
@@ -37,3 +44,38 @@ func NewTicker(
 		Prices:          [values.PricePeriodCount]int{},
 	}
 }
+
+// CanonicalKey returns a key that is the same for any two tickers that will always get
+// the same prediction, for caching predictions. Intervals for periods with a known
+// price are ignored, as the price takes precedence.
+//
+// The key is meant to be compared, not parsed. Its format may change between
+// versions.
+func (ticker *PriceTicker) CanonicalKey() string {
+	builder := new(strings.Builder)
+	builder.Grow(128)
+
+	builder.WriteString(strconv.Itoa(ticker.PurchasePrice))
+	builder.WriteByte('|')
+	builder.WriteString(strconv.Itoa(int(ticker.PreviousPattern)))
+	builder.WriteByte('|')
+	builder.WriteString(strconv.Itoa(int(ticker.CurrentPeriod)))
+
+	for period := 0; period < values.PricePeriodCount; period++ {
+		builder.WriteByte('|')
+		if price := ticker.Prices[period]; price != 0 {
+			builder.WriteString(strconv.Itoa(price))
+			continue
+		}
+
+		interval := ticker.Intervals[period]
+		if !interval.IsSet() {
+			continue
+		}
+		builder.WriteString(strconv.Itoa(interval.Min))
+		builder.WriteByte('-')
+		builder.WriteString(strconv.Itoa(interval.Max))
+	}
+
+	return builder.String()
+}
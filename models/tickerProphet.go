@@ -0,0 +1,155 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/values"
+	"golang.org/x/xerrors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The address Turnip Prophet share links point to.
+const turnipProphetAddress = "https://turnipprophet.io/"
+
+// Turnip Prophet uses -1 for a previous pattern the player does not know, and the
+// game's pattern indexes otherwise.
+const turnipProphetUnknownPattern = -1
+
+// Returns a Turnip Prophet share link for the ticker.
+//
+// Turnip Prophet links hold the purchase price followed by the 12 prices, separated
+// by periods, with unknown prices left empty. Price intervals and the current period
+// can't be described by a link, so they are left out.
+func (ticker *PriceTicker) TurnipProphetURL() string {
+	prices := make([]string, 0, values.PricePeriodCount+1)
+	prices = append(prices, turnipProphetPrice(ticker.PurchasePrice))
+	for _, price := range ticker.Prices {
+		prices = append(prices, turnipProphetPrice(price))
+	}
+
+	pattern := turnipProphetUnknownPattern
+	if ticker.PreviousPattern >= FLUCTUATING && ticker.PreviousPattern < UNKNOWN {
+		pattern = int(ticker.PreviousPattern)
+	}
+
+	// We build the query by hand rather than with url.Values so the prices keep
+	// their order and the periods are not escaped.
+	return turnipProphetAddress +
+		"?prices=" + strings.Join(prices, ".") +
+		"&pattern=" + strconv.Itoa(pattern) +
+		"&first=false"
+}
+
+func turnipProphetPrice(price int) string {
+	if price == 0 {
+		return ""
+	}
+	return strconv.Itoa(price)
+}
+
+// Creates a ticker from a Turnip Prophet share link. ``link`` can be the full link or
+// just its query string.
+//
+// Unknown prices can be empty or "NaN", and the previous pattern can be Turnip
+// Prophet's pattern index or a pattern name. The ticker's current period is set to
+// the last period with a price.
+//
+// Returns errs.ErrInvalidTurnipProphetURL if the link can't be read, and
+// errs.ErrTurnipProphetFirstBuy if the link is for a first-time buyer, as the
+// predictor does not cover a player's first week.
+func TickerFromTurnipProphetURL(link string) (*PriceTicker, error) {
+	rawQuery := link
+	if index := strings.Index(link, "?"); index >= 0 {
+		rawQuery = link[index+1:]
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, errs.ErrInvalidTurnipProphetURL)
+	}
+
+	if first, _ := strconv.ParseBool(query.Get("first")); first {
+		return nil, errs.ErrTurnipProphetFirstBuy
+	}
+
+	previousPattern, err := PatternFromTurnipProphet(query.Get("pattern"))
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, errs.ErrInvalidTurnipProphetURL)
+	}
+
+	ticker := NewTicker(0, previousPattern, 0)
+	if err := ticker.setTurnipProphetPrices(query.Get("prices")); err != nil {
+		return nil, err
+	}
+
+	return ticker, nil
+}
+
+// PatternFromTurnipProphet parses a pattern the way Turnip Prophet writes it: an index
+// where -1 is unknown. Pattern names, which some tools write instead, are parsed with
+// PatternFromString. An empty value is UNKNOWN. Returns errs.ErrPatternStringValue if
+// the value is neither.
+func PatternFromTurnipProphet(value string) (PricePattern, error) {
+	if value == "" {
+		return UNKNOWN, nil
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		pattern, err := PatternFromString(value)
+		if err != nil {
+			return UNKNOWN, xerrors.Errorf("pattern %q: %w", value, err)
+		}
+		return pattern, nil
+	}
+
+	if index == turnipProphetUnknownPattern {
+		return UNKNOWN, nil
+	}
+	if index < int(FLUCTUATING) || index > int(UNKNOWN) {
+		return UNKNOWN, xerrors.Errorf(
+			"pattern %v: %w", index, errs.ErrPatternStringValue,
+		)
+	}
+	return PricePattern(index), nil
+}
+
+// Sets the purchase price and prices from Turnip Prophet's period-separated price
+// list. Links may leave off unknown prices at the end of the week.
+func (ticker *PriceTicker) setTurnipProphetPrices(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	prices := strings.Split(value, ".")
+	if len(prices) > values.PricePeriodCount+1 {
+		return xerrors.Errorf(
+			"%v prices: %w", len(prices), errs.ErrInvalidTurnipProphetURL,
+		)
+	}
+
+	for i, priceValue := range prices {
+		if priceValue == "" || strings.EqualFold(priceValue, "NaN") {
+			continue
+		}
+
+		price, err := strconv.Atoi(priceValue)
+		if err != nil || price < 0 {
+			return xerrors.Errorf(
+				"price %q: %w", priceValue, errs.ErrInvalidTurnipProphetURL,
+			)
+		}
+
+		if i == 0 {
+			ticker.PurchasePrice = price
+			continue
+		}
+
+		period := PricePeriod(i - 1)
+		ticker.Prices[period] = price
+		ticker.CurrentPeriod = period
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+func TestTickerFromTurnipProphetURL(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker, err := TickerFromTurnipProphetURL(
+		"https://turnipprophet.io/?prices=102.89.85..78.NaN.&pattern=1&first=false",
+	)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+
+	assert.Equal(102, ticker.PurchasePrice)
+	assert.Equal(BIGSPIKE, ticker.PreviousPattern)
+	assert.Equal(
+		NookPriceArray{89, 85, 0, 78, 0, 0, 0, 0, 0, 0, 0, 0}, ticker.Prices,
+	)
+	assert.Equal(PricePeriod(3), ticker.CurrentPeriod)
+}
+
+func TestTickerFromTurnipProphetPatterns(t *testing.T) {
+	testCases := []struct {
+		Query    string
+		Expected PricePattern
+	}{
+		{"pattern=-1", UNKNOWN},
+		{"pattern=0", FLUCTUATING},
+		{"pattern=1", BIGSPIKE},
+		{"pattern=2", DECREASING},
+		{"pattern=3", SMALLSPIKE},
+		{"pattern=small%20spike", SMALLSPIKE},
+		{"prices=100", UNKNOWN},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Query, func(t *testing.T) {
+			ticker, err := TickerFromTurnipProphetURL("?" + testCase.Query)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, testCase.Expected, ticker.PreviousPattern)
+		})
+	}
+}
+
+func TestTickerFromTurnipProphetErrors(t *testing.T) {
+	testCases := []struct {
+		Query    string
+		Expected error
+	}{
+		{"prices=100.90&pattern=5", errs.ErrInvalidTurnipProphetURL},
+		{"prices=100.90&pattern=spiky", errs.ErrInvalidTurnipProphetURL},
+		{"prices=100.ninety", errs.ErrInvalidTurnipProphetURL},
+		{"prices=100.-90", errs.ErrInvalidTurnipProphetURL},
+		{"prices=1.2.3.4.5.6.7.8.9.10.11.12.13.14", errs.ErrInvalidTurnipProphetURL},
+		{"prices=%zz", errs.ErrInvalidTurnipProphetURL},
+		{"prices=100.90&first=true", errs.ErrTurnipProphetFirstBuy},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Query, func(t *testing.T) {
+			_, err := TickerFromTurnipProphetURL(testCase.Query)
+			assert.True(t, xerrors.Is(err, testCase.Expected), err)
+		})
+	}
+}
+
+func TestPatternFromTurnipProphet(t *testing.T) {
+	assert := assert.New(t)
+
+	for value, expected := range map[string]PricePattern{
+		"":          UNKNOWN,
+		"-1":        UNKNOWN,
+		"1":         BIGSPIKE,
+		"4":         UNKNOWN,
+		"big spike": BIGSPIKE,
+	} {
+		pattern, err := PatternFromTurnipProphet(value)
+		assert.NoError(err, value)
+		assert.Equal(expected, pattern, value)
+	}
+
+	for _, value := range []string{"5", "-2", "spiky"} {
+		_, err := PatternFromTurnipProphet(value)
+		assert.True(xerrors.Is(err, errs.ErrPatternStringValue), value)
+	}
+}
+
+func TestTurnipProphetURLRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(98, DECREASING, 5)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+	ticker.Prices[5] = 130
+	// Intervals can't be described by a link.
+	ticker.Intervals[2] = PriceInterval{Min: 70, Max: 80}
+
+	link := ticker.TurnipProphetURL()
+	assert.Equal(
+		"https://turnipprophet.io/?prices=98.86.82....130......&pattern=2&first=false",
+		link,
+	)
+
+	parsed, err := TickerFromTurnipProphetURL(link)
+	if !assert.NoError(err) {
+		t.FailNow()
+	}
+	assert.Equal(ticker.PurchasePrice, parsed.PurchasePrice)
+	assert.Equal(ticker.PreviousPattern, parsed.PreviousPattern)
+	assert.Equal(ticker.Prices, parsed.Prices)
+	assert.Equal(ticker.CurrentPeriod, parsed.CurrentPeriod)
+
+	unknown := NewTicker(0, UNKNOWN, 0)
+	assert.Equal(
+		"https://turnipprophet.io/?prices=............&pattern=-1&first=false",
+		unknown.TurnipProphetURL(),
+	)
+}
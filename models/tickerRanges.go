@@ -0,0 +1,94 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/values"
+	"golang.org/x/xerrors"
+)
+
+// PriceInterval is an inclusive range of bells a price is known to fall within, for
+// when we only know something like "it was around 120" or "over 200". A Min or Max of
+// 0 leaves that end of the range open.
+type PriceInterval struct {
+	Min int
+	Max int
+}
+
+// Whether anything is known about the price.
+func (interval PriceInterval) IsSet() bool {
+	return interval.Min != 0 || interval.Max != 0
+}
+
+// Whether ``price`` falls within the interval.
+func (interval PriceInterval) Contains(price int) bool {
+	return (interval.Min == 0 || price >= interval.Min) &&
+		(interval.Max == 0 || price <= interval.Max)
+}
+
+// Holds the price intervals for a week in price-period order.
+type PriceIntervalArray [values.PricePeriodCount]PriceInterval
+
+// Returns what is known about the price of a period as an inclusive range. An exact
+// price from Prices takes precedence over an interval. A bound of 0 is open-ended, and
+// ``known`` is false if nothing is known about the price.
+func (ticker *PriceTicker) observation(
+	period PricePeriod,
+) (low int, high int, known bool) {
+	if price := ticker.Prices[period]; price != 0 {
+		return price, price, true
+	}
+	interval := ticker.Intervals[period]
+	return interval.Min, interval.Max, interval.IsSet()
+}
+
+func (ticker *PriceTicker) setInterval(period PricePeriod, low int, high int) error {
+	if period < 0 || int(period) >= values.PricePeriodCount {
+		return xerrors.Errorf("period %v: %w", period, errs.ErrInvalidPriceInterval)
+	}
+	if low < 0 || high < 0 || (high != 0 && low > high) {
+		return xerrors.Errorf(
+			"%v to %v: %w", low, high, errs.ErrInvalidPriceInterval,
+		)
+	}
+	ticker.Intervals[period] = PriceInterval{Min: low, Max: high}
+	return nil
+}
+
+// Records that the price for ``period`` was between ``low`` and ``high`` bells,
+// inclusive.
+func (ticker *PriceTicker) SetRange(period PricePeriod, low int, high int) error {
+	if low == 0 || high == 0 {
+		return xerrors.Errorf(
+			"%v to %v: %w", low, high, errs.ErrInvalidPriceInterval,
+		)
+	}
+	return ticker.setInterval(period, low, high)
+}
+
+// Records that the price for ``period`` was ``price`` bells or more.
+func (ticker *PriceTicker) SetAtLeast(period PricePeriod, price int) error {
+	if price <= 0 {
+		return xerrors.Errorf("at least %v: %w", price, errs.ErrInvalidPriceInterval)
+	}
+	return ticker.setInterval(period, price, 0)
+}
+
+// Records that the price for ``period`` was ``price`` bells or less.
+func (ticker *PriceTicker) SetAtMost(period PricePeriod, price int) error {
+	if price <= 0 {
+		return xerrors.Errorf("at most %v: %w", price, errs.ErrInvalidPriceInterval)
+	}
+	return ticker.setInterval(period, 0, price)
+}
+
+// Records that the price for ``period`` was within ``tolerance`` bells of ``price``.
+func (ticker *PriceTicker) SetAround(
+	period PricePeriod, price int, tolerance int,
+) error {
+	if tolerance < 0 || price-tolerance <= 0 {
+		return xerrors.Errorf(
+			"around %v by %v: %w", price, tolerance, errs.ErrInvalidPriceInterval,
+		)
+	}
+	return ticker.setInterval(period, price-tolerance, price+tolerance)
+}
@@ -0,0 +1,141 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+func TestTickerIntervalSetters(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 4)
+	assert.NoError(ticker.SetRange(0, 80, 90))
+	assert.NoError(ticker.SetAtLeast(1, 200))
+	assert.NoError(ticker.SetAtMost(2, 70))
+	assert.NoError(ticker.SetAround(3, 120, 5))
+
+	assert.Equal(PriceInterval{Min: 80, Max: 90}, ticker.Intervals[0])
+	assert.Equal(PriceInterval{Min: 200}, ticker.Intervals[1])
+	assert.Equal(PriceInterval{Max: 70}, ticker.Intervals[2])
+	assert.Equal(PriceInterval{Min: 115, Max: 125}, ticker.Intervals[3])
+
+	assert.True(ticker.Intervals[1].Contains(600))
+	assert.False(ticker.Intervals[1].Contains(199))
+	assert.True(ticker.Intervals[2].Contains(1))
+	assert.False(ticker.Intervals[4].IsSet())
+
+	// Exact prices take precedence over intervals
+	ticker.Prices[0] = 95
+	low, high, known := ticker.observation(0)
+	assert.Equal(95, low)
+	assert.Equal(95, high)
+	assert.True(known)
+
+	for name, err := range map[string]error{
+		"range backwards": ticker.SetRange(0, 90, 80),
+		"range open":      ticker.SetRange(0, 0, 80),
+		"bad period":      ticker.SetRange(12, 80, 90),
+		"at least 0":      ticker.SetAtLeast(0, 0),
+		"at most -1":      ticker.SetAtMost(0, -1),
+		"around too wide": ticker.SetAround(0, 10, 10),
+	} {
+		assert.True(xerrors.Is(err, errs.ErrInvalidPriceInterval), name)
+	}
+}
+
+func predictIntervalTicker(t *testing.T, ticker *PriceTicker) *Prediction {
+	prediction, err := (&Predictor{Ticker: ticker}).Predict()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prediction
+}
+
+func TestIntervalSinglePriceMatchesExact(t *testing.T) {
+	assert := assert.New(t)
+
+	exact := NewTicker(100, UNKNOWN, 2)
+	exact.Prices[0] = 86
+	exact.Prices[1] = 82
+	exact.Prices[2] = 78
+
+	interval := NewTicker(100, UNKNOWN, 2)
+	interval.Prices[0] = 86
+	assert.NoError(interval.SetRange(1, 82, 82))
+	assert.NoError(interval.SetRange(2, 78, 78))
+
+	expected := predictIntervalTicker(t, exact)
+	actual := predictIntervalTicker(t, interval)
+
+	for i, pattern := range expected.Patterns {
+		assert.Equal(pattern.Chance(), actual.Patterns[i].Chance())
+		assert.Equal(
+			len(pattern.PotentialWeeks), len(actual.Patterns[i].PotentialWeeks),
+		)
+		assert.Equal(pattern.MinPrice(), actual.Patterns[i].MinPrice())
+		assert.Equal(pattern.MaxPrice(), actual.Patterns[i].MaxPrice())
+	}
+}
+
+func TestIntervalOver200(t *testing.T) {
+	assert := assert.New(t)
+
+	// Only a big spike can get over 200 bells.
+	ticker := NewTicker(100, UNKNOWN, 5)
+	assert.NoError(ticker.SetAtLeast(5, 201))
+
+	prediction := predictIntervalTicker(t, ticker)
+	assert.Equal(1.0, prediction.Patterns[BIGSPIKE].Chance())
+	assert.GreaterOrEqual(prediction.Bands.Period(5).MinPrice(), 201)
+	assert.InDelta(1, prediction.ChanceAtLeastInPeriod(201, 5), 0.0005)
+}
+
+func TestIntervalAround(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 1)
+	ticker.Prices[0] = 86
+	assert.NoError(ticker.SetAround(1, 82, 2))
+
+	prediction := predictIntervalTicker(t, ticker)
+	band := prediction.Bands.Period(1)
+	assert.GreaterOrEqual(band.MinPrice(), 80)
+	assert.LessOrEqual(band.MaxPrice(), 84)
+
+	// Every week that fits the interval has a price within it
+	for _, pattern := range prediction.Patterns {
+		for _, week := range pattern.PotentialWeeks {
+			assert.True(week.Prices[1].IsValidRange(80, 84))
+		}
+	}
+
+	// The interval narrows the following period of compounding patterns the same way
+	// an exact price would, at each end of the interval.
+	decreasing := prediction.Patterns[DECREASING]
+	if assert.Len(decreasing.PotentialWeeks, 1) {
+		period := decreasing.PotentialWeeks[0].Prices[2]
+		assert.Less(period.MaxPrice(), 84)
+		assert.Greater(period.GuaranteedPrice(), 70)
+	}
+}
+
+func TestIntervalUninformative(t *testing.T) {
+	assert := assert.New(t)
+
+	unknown := predictIntervalTicker(t, NewTicker(100, UNKNOWN, 0))
+
+	ticker := NewTicker(100, UNKNOWN, 0)
+	assert.NoError(ticker.SetRange(0, 1, 1000))
+	wide := predictIntervalTicker(t, ticker)
+
+	for i, pattern := range unknown.Patterns {
+		assert.Equal(
+			len(pattern.PotentialWeeks), len(wide.Patterns[i].PotentialWeeks),
+		)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/peake100/turnup-go/values"
+)
+
+// Validate checks that the ticker describes a week the game could make. Every
+// problem is reported, as an errs.TickerErrors holding an errs.TickerFieldError for
+// each one. Returns nil if the ticker is valid.
+func (ticker *PriceTicker) Validate() error {
+	return ticker.validate(false)
+}
+
+// Validates the ticker. If ``allowMistakes`` is true, prices over the highest price
+// the game can make are allowed, as they can be treated as mistakes when predicting
+// with an ErrorRate.
+func (ticker *PriceTicker) validate(allowMistakes bool) error {
+	var problems errs.TickerErrors
+	addProblem := func(field string, period int, err error) {
+		problems = append(
+			problems, &errs.TickerFieldError{Field: field, Period: period, Err: err},
+		)
+	}
+
+	if ticker.PurchasePrice != 0 &&
+		(ticker.PurchasePrice < minPurchasePrice ||
+			ticker.PurchasePrice > maxPurchasePrice) {
+		addProblem("PurchasePrice", -1, errs.ErrInvalidPurchasePrice)
+	}
+
+	if !ticker.PreviousPattern.valid() {
+		addProblem("PreviousPattern", -1, errs.ErrInvalidPreviousPattern)
+	}
+
+	currentPeriodValid := ticker.CurrentPeriod >= 0 &&
+		ticker.CurrentPeriod < values.PricePeriodCount
+	if !currentPeriodValid {
+		addProblem("CurrentPeriod", -1, errs.ErrInvalidCurrentPeriod)
+	}
+
+	for period := PricePeriod(0); period < values.PricePeriodCount; period++ {
+		price := ticker.Prices[period]
+		if price < 0 || (price > maxPossiblePrice && !allowMistakes) {
+			addProblem("Prices", int(period), errs.ErrInvalidTickerPrice)
+		}
+
+		interval := ticker.Intervals[period]
+		if interval.Min < 0 || interval.Max < 0 ||
+			(interval.Max != 0 && interval.Min > interval.Max) {
+			addProblem("Intervals", int(period), errs.ErrInvalidPriceInterval)
+		}
+
+		// We can only check for prices from the future if we know when now is.
+		if !currentPeriodValid || period <= ticker.CurrentPeriod {
+			continue
+		}
+		if price != 0 {
+			addProblem("Prices", int(period), errs.ErrPriceAfterCurrentPeriod)
+		}
+		if interval.IsSet() {
+			addProblem("Intervals", int(period), errs.ErrPriceAfterCurrentPeriod)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
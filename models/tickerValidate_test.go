@@ -0,0 +1,102 @@
+package models
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"github.com/peake100/turnup-go/errs"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"testing"
+)
+
+func TestValidateTickerValid(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, SMALLSPIKE, 3)
+	ticker.Prices[0] = 86
+	ticker.Prices[3] = 660
+	ticker.Intervals[1] = PriceInterval{Min: 80, Max: 90}
+	assert.NoError(ticker.Validate())
+
+	// Unknown purchase prices are fine.
+	assert.NoError(NewTicker(0, UNKNOWN, 0).Validate())
+}
+
+func TestValidateTickerProblems(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(120, PricePattern(7), 4)
+	ticker.Prices[1] = -5
+	ticker.Prices[2] = 700
+	ticker.Prices[6] = 100
+	ticker.Intervals[3] = PriceInterval{Min: 90, Max: 80}
+	ticker.Intervals[8] = PriceInterval{Min: 80, Max: 90}
+
+	err := ticker.Validate()
+	if !assert.Error(err) {
+		t.FailNow()
+	}
+
+	var problems errs.TickerErrors
+	if !assert.True(xerrors.As(err, &problems)) {
+		t.FailNow()
+	}
+
+	expected := []errs.TickerFieldError{
+		{Field: "PurchasePrice", Period: -1, Err: errs.ErrInvalidPurchasePrice},
+		{Field: "PreviousPattern", Period: -1, Err: errs.ErrInvalidPreviousPattern},
+		{Field: "Prices", Period: 1, Err: errs.ErrInvalidTickerPrice},
+		{Field: "Prices", Period: 2, Err: errs.ErrInvalidTickerPrice},
+		{Field: "Intervals", Period: 3, Err: errs.ErrInvalidPriceInterval},
+		{Field: "Prices", Period: 6, Err: errs.ErrPriceAfterCurrentPeriod},
+		{Field: "Intervals", Period: 8, Err: errs.ErrPriceAfterCurrentPeriod},
+	}
+	if !assert.Len(problems, len(expected)) {
+		t.FailNow()
+	}
+	for i, problem := range problems {
+		assert.Equal(expected[i], *problem, "problem %v", i)
+	}
+
+	assert.True(xerrors.Is(err, errs.ErrInvalidTicker))
+	assert.True(xerrors.Is(err, errs.ErrInvalidPurchasePrice))
+	assert.True(xerrors.Is(err, errs.ErrPriceAfterCurrentPeriod))
+	assert.False(xerrors.Is(err, errs.ErrInvalidCurrentPeriod))
+
+	var fieldErr *errs.TickerFieldError
+	if assert.True(xerrors.As(err, &fieldErr)) {
+		assert.Equal("PurchasePrice", fieldErr.Field)
+	}
+
+	assert.Contains(err.Error(), "Prices[2]: ")
+}
+
+func TestValidateTickerCurrentPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, UNKNOWN, 12)
+	err := ticker.Validate()
+	assert.True(xerrors.Is(err, errs.ErrInvalidCurrentPeriod))
+
+	// We don't know when now is, so we can't say any prices are from the future.
+	ticker.Prices[11] = 100
+	assert.False(xerrors.Is(ticker.Validate(), errs.ErrPriceAfterCurrentPeriod))
+}
+
+// Predictions should return validation errors rather than panicking.
+func TestValidateTickerPredict(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, ticker := range []*PriceTicker{
+		NewTicker(100, PricePattern(7), 0),
+		NewTicker(100, PricePattern(-1), 0),
+		NewTicker(100, UNKNOWN, 20),
+		NewTicker(100, UNKNOWN, -1),
+	} {
+		assert.NotPanics(func() {
+			_, err := (&Predictor{Ticker: ticker}).Predict()
+			assert.True(xerrors.Is(err, errs.ErrInvalidTicker))
+		})
+	}
+}
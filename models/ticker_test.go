@@ -205,3 +205,35 @@ func TestTickerPriceForTimeSunday(t *testing.T) {
 	testSundayErr(t, err)
 	assert.Equal(t, price, 0)
 }
+
+func TestCanonicalKey(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewTicker(100, FLUCTUATING, 2)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+	assert.Equal("100|0|2|86|82||||||||||", ticker.CanonicalKey())
+
+	// An interval for a period with a price is ignored.
+	same := *ticker
+	same.Intervals[1] = PriceInterval{Min: 60, Max: 70}
+	assert.Equal(ticker.CanonicalKey(), same.CanonicalKey())
+
+	withInterval := *ticker
+	assert.NoError(withInterval.SetAtLeast(2, 100))
+	assert.Equal("100|0|2|86|82|100-0|||||||||", withInterval.CanonicalKey())
+
+	// Everything else that changes the prediction changes the key.
+	changes := []func(changed *PriceTicker){
+		func(changed *PriceTicker) { changed.PurchasePrice = 0 },
+		func(changed *PriceTicker) { changed.PreviousPattern = UNKNOWN },
+		func(changed *PriceTicker) { changed.CurrentPeriod = 3 },
+		func(changed *PriceTicker) { changed.Prices[11] = 100 },
+		func(changed *PriceTicker) { changed.Intervals[11] = PriceInterval{Max: 100} },
+	}
+	for i, change := range changes {
+		changed := *ticker
+		change(&changed)
+		assert.NotEqual(ticker.CanonicalKey(), changed.CanonicalKey(), "change %v", i)
+	}
+}
@@ -0,0 +1,200 @@
+package turnup
+
+//revive:disable:import-shadowing reason: Disabled for assert := assert.New(), which is
+// the preferred method of using multiple asserts in a test.
+
+import (
+	"fmt"
+	"github.com/peake100/turnup-go/calibration"
+	"github.com/peake100/turnup-go/internal/gamesim"
+	"github.com/peake100/turnup-go/models"
+	"github.com/peake100/turnup-go/models/patterns"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// A week simulated by the game's routine, along with the ticker a player would have
+// for it when only some of the information is known.
+type monteCarloWeek struct {
+	Week   *gamesim.Week
+	Ticker *models.PriceTicker
+}
+
+// Generates weeks for a number of islands by running the game's routine for several
+// weeks in a row on each one, so the previous pattern of each week is as likely as it
+// would be in the game. Each week then has a random subset of its information hidden.
+func newMonteCarloWeeks(seed int64, count int) []*monteCarloWeek {
+	const weeksPerIsland = 10
+	const burnInWeeks = 5
+
+	hider := rand.New(rand.NewSource(seed))
+	weeks := make([]*monteCarloWeek, 0, count)
+
+	for island := 0; len(weeks) < count; island++ {
+		random := new(gamesim.Random)
+		random.Seed(uint32(seed) + uint32(island))
+
+		previousPattern := gamesim.Pattern(random.Int(0, 3))
+		for i := 0; i < burnInWeeks+weeksPerIsland && len(weeks) < count; i++ {
+			week := gamesim.Simulate(random, previousPattern)
+			previousPattern = week.Pattern
+			if i < burnInWeeks {
+				continue
+			}
+
+			weeks = append(weeks, &monteCarloWeek{
+				Week:   week,
+				Ticker: hideWeekInfo(hider, week),
+			})
+		}
+	}
+
+	return weeks
+}
+
+// Makes a ticker for a simulated week, with the previous pattern, the purchase price
+// and the prices each hidden at random. Prices after the current period are always
+// hidden.
+func hideWeekInfo(hider *rand.Rand, week *gamesim.Week) *models.PriceTicker {
+	previousPattern := models.PricePattern(week.PreviousPattern)
+	if hider.Float64() < 0.25 {
+		previousPattern = patterns.UNKNOWN
+	}
+
+	purchasePrice := week.PurchasePrice
+	if hider.Float64() < 0.1 {
+		purchasePrice = 0
+	}
+
+	currentPeriod := models.PricePeriod(hider.Intn(gamesim.PricePeriodCount))
+	ticker := NewPriceTicker(purchasePrice, previousPattern, currentPeriod)
+	for period := models.PricePeriod(0); period <= currentPeriod; period++ {
+		if hider.Float64() < 0.7 {
+			ticker.Prices[period] = week.Prices[period]
+		}
+	}
+
+	return ticker
+}
+
+// Returns true if one of the potential weeks for the pattern could be the simulated
+// week, including every price that was hidden from the prediction.
+func weekIsPredicted(
+	prediction *models.Prediction, week *gamesim.Week,
+) bool {
+	pattern := prediction.Patterns[week.Pattern]
+
+	for _, potentialWeek := range pattern.PotentialWeeks {
+		if potentialWeek.PurchasePrice != week.PurchasePrice {
+			continue
+		}
+
+		fits := true
+		for period, price := range week.Prices {
+			potentialPeriod := potentialWeek.Prices[period]
+			if price < potentialPeriod.MinPrice() || price > potentialPeriod.MaxPrice() {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Makes the calibration outcome for a simulated week, with the information in
+// ``ticker``.
+func newMonteCarloOutcome(
+	week *gamesim.Week, ticker *models.PriceTicker,
+) *calibration.Outcome {
+	return &calibration.Outcome{
+		Ticker:  ticker,
+		Pattern: models.PricePattern(week.Pattern),
+		Prices:  week.Prices,
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	weekCount := 1000
+	if testing.Short() {
+		weekCount = 100
+	}
+
+	outcomes := make([]*calibration.Outcome, 0, weekCount)
+	priorOutcomes := make([]*calibration.Outcome, 0, weekCount)
+
+	for i, simulated := range newMonteCarloWeeks(1, weekCount) {
+		week := simulated.Week
+
+		prediction, err := Predict(simulated.Ticker)
+		if !assert.NoError(t, err, "week %v: %+v", i, week) {
+			continue
+		}
+
+		// The predictor must never rule out what actually happened.
+		assert.True(
+			t,
+			weekIsPredicted(prediction, week),
+			"week %v: simulated week not predicted: %+v, ticker: %+v",
+			i,
+			week,
+			simulated.Ticker,
+		)
+
+		// Compare to the prediction we would have made without any prices.
+		priorTicker := NewPriceTicker(
+			simulated.Ticker.PurchasePrice, simulated.Ticker.PreviousPattern, 0,
+		)
+		outcomes = append(outcomes, newMonteCarloOutcome(week, simulated.Ticker))
+		priorOutcomes = append(priorOutcomes, newMonteCarloOutcome(week, priorTicker))
+	}
+
+	report, err := calibration.Evaluate(outcomes)
+	if !assert.NoError(t, err, "evaluate") {
+		t.FailNow()
+	}
+	priorReport, err := calibration.Evaluate(priorOutcomes)
+	if !assert.NoError(t, err, "evaluate without prices") {
+		t.FailNow()
+	}
+
+	// Every simulated week came from the game's routine, so it must be predictable.
+	assert.Empty(t, report.Skipped, "skipped outcomes")
+	assert.Empty(t, priorReport.Skipped, "skipped outcomes without prices")
+
+	t.Run("brier_score", func(t *testing.T) {
+		t.Logf(
+			"brier score: %.4f, without prices: %.4f", report.Brier, priorReport.Brier,
+		)
+
+		// Knowing some prices should always help.
+		assert.Less(t, report.Brier, priorReport.Brier)
+	})
+
+	t.Run("reliability", func(t *testing.T) {
+		for i, bin := range report.Reliability {
+			// Bins with too few samples are too noisy to tell us anything.
+			if bin.Count < 50 {
+				continue
+			}
+
+			t.Logf(
+				"bin %v: %v samples, predicted %.3f, happened %.3f",
+				i, bin.Count, bin.Predicted, bin.Observed,
+			)
+
+			// Allow for four standard errors of sampling noise, plus a little leeway
+			// for the predictor's approximations.
+			tolerance := 4*math.Sqrt(bin.Predicted*(1-bin.Predicted)/float64(bin.Count)) +
+				0.03
+			assert.InDelta(
+				t, bin.Predicted, bin.Observed, tolerance, fmt.Sprintf("bin %v", i),
+			)
+		}
+	})
+}
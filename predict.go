@@ -8,8 +8,14 @@ import (
 // Predict function
 var NewPriceTicker = models.NewTicker
 
+// Creates a ticker from a Turnip Prophet share link.
+var TickerFromTurnipProphetURL = models.TickerFromTurnipProphetURL
+
 type Prediction = models.Prediction
 
+// PredictOptions changes how a prediction is made, such as the chance of each pattern.
+type PredictOptions = models.PredictOptions
+
 // Predict the possible price patterns given the current week's turnip prices on an
 // island.
 func Predict(currentWeek *models.PriceTicker) (*Prediction, error) {
@@ -18,3 +24,33 @@ func Predict(currentWeek *models.PriceTicker) (*Prediction, error) {
 	}
 	return thisPredictor.Predict()
 }
+
+// Predict the possible price patterns given the current week's turnip prices on an
+// island, with options that change how the prediction is made.
+func PredictWithOptions(
+	currentWeek *models.PriceTicker, options PredictOptions,
+) (*Prediction, error) {
+	thisPredictor := &models.Predictor{
+		Ticker:         currentWeek,
+		PredictOptions: options,
+	}
+	return thisPredictor.Predict()
+}
+
+// BatchResult is the prediction for one ticker of a batch.
+type BatchResult = models.BatchResult
+
+// Predict the possible price patterns for many tickers at once. Results are returned
+// in the same order as the tickers.
+func PredictBatch(
+	tickers []*models.PriceTicker, options PredictOptions,
+) []*BatchResult {
+	return models.PredictBatch(tickers, options)
+}
+
+// PredictionCache stores predictions for tickers it has seen, so identical tickers
+// are only predicted once.
+type PredictionCache = models.PredictionCache
+
+// Makes a PredictionCache that holds up to ``size`` predictions.
+var NewPredictionCache = models.NewPredictionCache
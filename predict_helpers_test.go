@@ -417,6 +417,15 @@ func testSpikesDensity(
 		),
 	)
 
+	testSpikesPeakDensity(t, prediction.Spikes.Big(), bigSpike.Chance(), "big")
+	testSpikesPeakDensity(t, prediction.Spikes.Small(), smallSpike.Chance(), "small")
+	testSpikesPeakDensity(
+		t,
+		prediction.Spikes.Any(),
+		bigSpike.Chance()+smallSpike.Chance(),
+		"any",
+	)
+
 	anyVariance := math.Abs(anySpikeTotal - (smallSpikeTotal + bigSpikeTotal))
 	assert.Less(
 		anyVariance,
@@ -431,6 +440,32 @@ func testSpikesDensity(
 	)
 }
 
+// A spike can only peak once a week, so the peak breakdown should add up to the chance
+// of the spike. The peak prices should only be empty when the spike is impossible.
+func testSpikesPeakDensity(
+	t *testing.T, spike models.HasSpikeChance, expectedChance float64, name string,
+) {
+	assert := assert.New(t)
+
+	var peakTotal float64
+	for _, chance := range spike.PeakBreakdown() {
+		peakTotal += chance
+	}
+
+	assert.InDelta(
+		expectedChance,
+		peakTotal,
+		0.0005,
+		fmt.Sprintf("%v spike peak density total", name),
+	)
+
+	assert.Equal(
+		spike.Has(),
+		!spike.PeakPrices().IsEmpty(),
+		fmt.Sprintf("%v spike peak prices", name),
+	)
+}
+
 // We can use this function to test a prediction for a given ticker against our expected
 // results
 func testPrediction(
@@ -7,7 +7,10 @@ import (
 	"github.com/peake100/turnup-go/errs"
 	"github.com/peake100/turnup-go/models"
 	"github.com/peake100/turnup-go/models/patterns"
+	"github.com/peake100/turnup-go/values"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"math"
 	"testing"
 )
 
@@ -443,6 +446,9 @@ func TestUnknownBellPurchase(t *testing.T) {
 
 	ticker := NewPriceTicker(0, patterns.UNKNOWN, 0)
 
+	// Every permutation is predicted once for each purchase price from 90 - 110.
+	const purchasePrices = 21
+
 	expected := &expectedPrediction{
 		Prices: PriceRange{
 			Min:        9,
@@ -466,7 +472,7 @@ func TestUnknownBellPurchase(t *testing.T) {
 				Guaranteed: 81,
 				Max:        154,
 			},
-			PossibleWeeks: 56,
+			PossibleWeeks: 56 * purchasePrices,
 			MinPricePeriods: []models.PricePeriod{
 				2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 			},
@@ -489,7 +495,7 @@ func TestUnknownBellPurchase(t *testing.T) {
 				Guaranteed: 180,
 				Max:        660,
 			},
-			PossibleWeeks: 7,
+			PossibleWeeks: 7 * purchasePrices,
 			Spike: expectedSpike{
 				Small:      false,
 				SmallStart: 0,
@@ -514,7 +520,7 @@ func TestUnknownBellPurchase(t *testing.T) {
 				Guaranteed: 77,
 				Max:        99,
 			},
-			PossibleWeeks:          1,
+			PossibleWeeks:          1 * purchasePrices,
 			MinPricePeriods:        []models.PricePeriod{11},
 			GuaranteedPricePeriods: []models.PricePeriod{0},
 			MaxPricePeriods:        []models.PricePeriod{0},
@@ -531,7 +537,7 @@ func TestUnknownBellPurchase(t *testing.T) {
 				Guaranteed: 126,
 				Max:        220,
 			},
-			PossibleWeeks: 8,
+			PossibleWeeks: 8 * purchasePrices,
 			Spike: expectedSpike{
 				Small:      true,
 				SmallStart: 2,
@@ -832,3 +838,311 @@ func Test100BellPurchaseUnlikelyUpperBoundPattern(t *testing.T) {
 
 	testPrediction(t, expected, ticker)
 }
+
+// Test that the price bands line up with the price ranges and known prices of a
+// prediction
+func TestPriceBands(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewPriceTicker(100, patterns.DECREASING, 1)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+
+	prediction, err := Predict(ticker)
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	// Known prices are certain.
+	monday := prediction.Bands.Period(0)
+	assert.Equal(1.0, monday.Chance(86), "known price chance")
+	assert.Equal(86, monday.Band().P90, "known price p90")
+
+	for i := 0; i < values.PricePeriodCount; i++ {
+		band := prediction.Bands.Period(models.PricePeriod(i)).Band()
+		assert.LessOrEqual(band.P10, band.P25, "p10 <= p25")
+		assert.LessOrEqual(band.P25, band.P50, "p25 <= p50")
+		assert.LessOrEqual(band.P50, band.P75, "p50 <= p75")
+		assert.LessOrEqual(band.P75, band.P90, "p75 <= p90")
+	}
+
+	weeklyMax := prediction.Bands.WeeklyMax()
+	assert.GreaterOrEqual(weeklyMax.MinPrice(), prediction.GuaranteedPrice())
+	assert.LessOrEqual(weeklyMax.MaxPrice(), prediction.MaxPrice())
+	assert.Equal(weeklyMax.MaxPrice(), prediction.Bands.FutureMax().MaxPrice())
+
+	// The decreasing pattern peaks on monday morning, which is known.
+	decreasing, _ := prediction.Patterns.Get(patterns.DECREASING)
+	assert.Equal(1.0, decreasing.Bands.WeeklyMax().Chance(86), "decreasing max")
+	assert.Equal(82, decreasing.Bands.FutureMax().MaxPrice(), "decreasing future")
+
+	// Patterns that have been ruled out have empty bands
+	fluctuating, _ := prediction.Patterns.Get(patterns.FLUCTUATING)
+	assert.True(fluctuating.Bands.WeeklyMax().IsEmpty(), "fluctuating empty")
+
+	// Every week gets its own bands
+	bigSpike, _ := prediction.Patterns.Get(patterns.BIGSPIKE)
+	for _, week := range bigSpike.PotentialWeeks {
+		assert.LessOrEqual(week.Bands.WeeklyMax().MaxPrice(), week.MaxPrice())
+	}
+}
+
+// Test that the weekly max follows the phases of a week rather than treating every
+// price period as independent
+func TestWeeklyMaxFollowsPhases(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := Predict(NewPriceTicker(100, patterns.UNKNOWN, 0))
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	// Prices only ever fall during a decreasing week, so the highest price is monday
+	// morning's.
+	decreasing, _ := prediction.Patterns.Get(patterns.DECREASING)
+	for _, week := range decreasing.PotentialWeeks {
+		assertSameDistribution(t, week.Bands.Period(0), week.Bands.WeeklyMax())
+	}
+
+	// Nothing else in a big spike week comes close to the peak of the spike.
+	bigSpike, _ := prediction.Patterns.Get(patterns.BIGSPIKE)
+	for _, week := range bigSpike.PotentialWeeks {
+		for i, potentialPeriod := range week.Prices {
+			if !potentialPeriod.Spikes.IsPeak() {
+				continue
+			}
+			peak := week.Bands.Period(models.PricePeriod(i))
+			assertSameDistribution(t, peak, week.Bands.WeeklyMax())
+		}
+	}
+}
+
+func assertSameDistribution(
+	t *testing.T, expected *models.PriceDistribution, actual *models.PriceDistribution,
+) {
+	assert := assert.New(t)
+
+	assert.Equal(expected.MinPrice(), actual.MinPrice(), "min price")
+	assert.Equal(expected.MaxPrice(), actual.MaxPrice(), "max price")
+	for price := expected.MinPrice(); price <= expected.MaxPrice(); price++ {
+		assert.InDelta(expected.Chance(price), actual.Chance(price), 0.000001, price)
+	}
+}
+
+// Test the chance of hitting a target price
+func TestChanceAtLeast(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewPriceTicker(100, patterns.DECREASING, 1)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+
+	prediction, err := Predict(ticker)
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	bigSpike, _ := prediction.Patterns.Get(patterns.BIGSPIKE)
+
+	// Only a big spike can reach 200 bells or more. The small spike's max price is 200
+	// bells, but its peak never quite gets there.
+	assert.InDelta(bigSpike.Chance(), prediction.ChanceAtLeast(200), 0.0005)
+	assert.InDelta(1, bigSpike.ChanceAtLeast(200), 0.0005, "big spike 200")
+	assert.InDelta(1, prediction.ChanceAtLeast(82), 0.0005, "current price")
+	assert.Equal(0.0, prediction.ChanceAtLeast(601), "over max")
+
+	// Past prices are certain, but not part of the future.
+	assert.Equal(1.0, prediction.ChanceAtLeastInPeriod(86, 0), "known price")
+	assert.Equal(0.0, prediction.ChanceAtLeastInPeriod(87, 0), "over known price")
+	assert.Equal(0.0, prediction.ChanceAtLeastBy(86, 0), "before current period")
+
+	// The earliest a big spike can happen is wednesday morning
+	assert.Equal(0.0, prediction.ChanceAtLeastBy(200, 3), "by tuesday afternoon")
+	assert.Greater(prediction.ChanceAtLeastBy(200, 4), 0.0, "by wednesday morning")
+
+	previous := 0.0
+	for period := models.PricePeriod(1); period < 12; period++ {
+		chance := prediction.ChanceAtLeastBy(150, period)
+		assert.GreaterOrEqual(chance, previous, "chance by period increases")
+		assert.LessOrEqual(
+			prediction.ChanceAtLeastInPeriod(150, period),
+			chance,
+			"chance in period less than chance by period",
+		)
+		previous = chance
+	}
+	assert.InDelta(previous, prediction.ChanceAtLeast(150), 0.0005, "by saturday")
+}
+
+// Test that the chance of hitting a target price follows the phases of a week
+func TestChanceAtLeastByFollowsPhases(t *testing.T) {
+	assert := assert.New(t)
+
+	prediction, err := Predict(NewPriceTicker(100, patterns.UNKNOWN, 0))
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	// Prices only ever fall during a decreasing week, so waiting longer than monday
+	// morning never helps.
+	decreasing, _ := prediction.Patterns.Get(patterns.DECREASING)
+	for _, week := range decreasing.PotentialWeeks {
+		monday := week.ChanceAtLeastInPeriod(88, 0)
+		assert.Greater(monday, 0.0, "monday morning")
+		for period := models.PricePeriod(0); period < 12; period++ {
+			assert.InDelta(monday, week.ChanceAtLeastBy(88, period), 0.000001, period)
+		}
+	}
+	assert.InDelta(
+		decreasing.ChanceAtLeastInPeriod(88, 0),
+		decreasing.ChanceAtLeastBy(88, 11),
+		0.000001,
+		"decreasing pattern",
+	)
+}
+
+// Test the timing and height of spike peaks when every week is possible
+func TestSpikePeaks(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewPriceTicker(100, patterns.UNKNOWN, 0)
+	prediction, err := Predict(ticker)
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	// The big spike peaks on the third day of the sharp increase, which can start
+	// anywhere from monday afternoon to thursday afternoon.
+	bigBreakdown := prediction.Spikes.Big().PeakBreakdown()
+	for period, chance := range bigBreakdown {
+		if period < 3 || period > 9 {
+			assert.Equal(0.0, chance, "big spike peak period %v", period)
+		} else {
+			assert.InDelta(0.2625/7, chance, 0.0001, "big spike peak %v", period)
+		}
+	}
+
+	// The small spike peaks in the middle of its three spike periods, not on the
+	// "peak minus one" periods to either side.
+	smallBreakdown := prediction.Spikes.Small().PeakBreakdown()
+	for period, chance := range smallBreakdown {
+		if period < 3 || period > 10 {
+			assert.Equal(0.0, chance, "small spike peak period %v", period)
+		} else {
+			assert.InDelta(0.25/8, chance, 0.0001, "small spike peak %v", period)
+		}
+	}
+
+	bigPeak := prediction.Spikes.Big().PeakPrices()
+	assert.Equal(201, bigPeak.MinPrice(), "big peak min")
+	assert.Equal(599, bigPeak.MaxPrice(), "big peak max")
+	assert.Equal(400, bigPeak.Quantile(0.5), "big peak median")
+
+	smallPeak := prediction.Spikes.Small().PeakPrices()
+	assert.Equal(140, smallPeak.MinPrice(), "small peak min")
+	assert.Equal(199, smallPeak.MaxPrice(), "small peak max")
+	assert.Equal(170, smallPeak.Quantile(0.5), "small peak median")
+
+	anyPeak := prediction.Spikes.Any().PeakPrices()
+	assert.Equal(140, anyPeak.MinPrice(), "any peak min")
+	assert.Equal(599, anyPeak.MaxPrice(), "any peak max")
+}
+
+func TestPredictWithPriors(t *testing.T) {
+	assert := assert.New(t)
+
+	// We are sure it's not decreasing, and have no other outside information.
+	options := PredictOptions{
+		Priors: map[models.PricePattern]float64{
+			patterns.FLUCTUATING: 1,
+			patterns.BIGSPIKE:    1,
+			patterns.SMALLSPIKE:  1,
+		},
+	}
+
+	prediction, err := PredictWithOptions(
+		NewPriceTicker(100, patterns.UNKNOWN, 0), options,
+	)
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	for _, pattern := range prediction.Patterns {
+		if pattern.Pattern == patterns.DECREASING {
+			assert.Equal(0.0, pattern.Chance(), "decreasing")
+			assert.Len(pattern.PotentialWeeks, 0, "decreasing weeks")
+			continue
+		}
+		assert.InDelta(1.0/3.0, pattern.Chance(), 0.0001, pattern.Pattern.String())
+	}
+}
+
+func TestPredictWithTransitions(t *testing.T) {
+	assert := assert.New(t)
+
+	ticker := NewPriceTicker(100, patterns.FLUCTUATING, 1)
+	ticker.Prices[0] = 86
+	ticker.Prices[1] = 82
+
+	expected, err := Predict(ticker)
+	if !assert.NoError(err, "predict") {
+		t.FailNow()
+	}
+
+	prediction, err := PredictWithOptions(
+		ticker, PredictOptions{Transitions: models.DefaultTransitionMatrix()},
+	)
+	if !assert.NoError(err, "predict default transitions") {
+		t.FailNow()
+	}
+	for i, pattern := range prediction.Patterns {
+		assert.Equal(expected.Patterns[i].Chance(), pattern.Chance())
+	}
+
+	// A community measured matrix where fluctuating is always followed by a big spike
+	transitions := models.DefaultTransitionMatrix()
+	transitions[patterns.FLUCTUATING] = [4]float64{0, 1, 0, 0}
+
+	prediction, err = PredictWithOptions(
+		ticker, PredictOptions{Transitions: transitions},
+	)
+	if !assert.NoError(err, "predict custom transitions") {
+		t.FailNow()
+	}
+	bigSpike, _ := prediction.Patterns.Get(patterns.BIGSPIKE)
+	assert.Equal(1.0, bigSpike.Chance())
+}
+
+func TestPredictWithOptionsErrors(t *testing.T) {
+	ticker := NewPriceTicker(100, patterns.UNKNOWN, 0)
+
+	_, err := PredictWithOptions(
+		ticker, PredictOptions{Priors: map[models.PricePattern]float64{}},
+	)
+	assert.True(t, xerrors.Is(err, errs.ErrNoPatternChance), "no chance")
+
+	_, err = PredictWithOptions(
+		ticker,
+		PredictOptions{Priors: map[models.PricePattern]float64{patterns.BIGSPIKE: -1}},
+	)
+	assert.True(t, xerrors.Is(err, errs.ErrNegativePatternChance), "negative")
+
+	for _, chance := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		_, err = PredictWithOptions(
+			ticker,
+			PredictOptions{
+				Priors: map[models.PricePattern]float64{patterns.BIGSPIKE: chance},
+			},
+		)
+		assert.True(
+			t, xerrors.Is(err, errs.ErrNonFinitePatternChance), "prior %v", chance,
+		)
+
+		transitions := models.DefaultTransitionMatrix()
+		transitions[patterns.UNKNOWN][patterns.DECREASING] = chance
+		_, err = PredictWithOptions(ticker, PredictOptions{Transitions: transitions})
+		assert.True(
+			t, xerrors.Is(err, errs.ErrNonFinitePatternChance), "transition %v", chance,
+		)
+	}
+}